@@ -3,13 +3,27 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
+	_ "time/tzdata" // quiet hour time zones without system zoneinfo
 
+	"github.com/trogers1052/alert-service/internal/admin"
+	"github.com/trogers1052/alert-service/internal/classify"
 	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/email"
 	"github.com/trogers1052/alert-service/internal/kafka"
+	"github.com/trogers1052/alert-service/internal/market"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/notify"
+	"github.com/trogers1052/alert-service/internal/portfolio"
+	"github.com/trogers1052/alert-service/internal/rules"
 	"github.com/trogers1052/alert-service/internal/service"
+	"github.com/trogers1052/alert-service/internal/storage"
 	"github.com/trogers1052/alert-service/internal/telegram"
 )
 
@@ -26,6 +40,11 @@ func main() {
 	log.Printf("  Kafka brokers: %v", cfg.KafkaBrokers)
 	log.Printf("  Decision topic: %s", cfg.KafkaDecisionTopic)
 	log.Printf("  Ranking topic: %s", cfg.KafkaRankingTopic)
+	if cfg.EnableMarketData {
+		log.Printf("  Quote topic: %s", cfg.KafkaQuoteTopic)
+		log.Printf("  Indicator topic: %s", cfg.KafkaIndicatorTopic)
+		log.Printf("  Market data max age: %ds", cfg.MarketDataMaxAgeSecs)
+	}
 	log.Printf("  Min confidence: %.2f", cfg.MinConfidence)
 	log.Printf("  Alert on BUY: %v, SELL: %v, WATCH: %v",
 		cfg.AlertOnBuy, cfg.AlertOnSell, cfg.AlertOnWatch)
@@ -34,16 +53,178 @@ func main() {
 	// Create Telegram client
 	telegramClient := telegram.NewClient(cfg.TelegramBotToken, cfg.TelegramChatID)
 
+	// Create in-memory market state
+	marketState := market.NewState(time.Duration(cfg.MarketDataMaxAgeSecs) * time.Second)
+
+	// Create context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Open persistent storage
+	store, err := openStore(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	if store != nil {
+		defer store.Close()
+
+		// Deliver to stored subscribers, seeded with the configured chat
+		if err := storage.EnsureSubscriber(ctx, store, cfg.TelegramChatID); err != nil {
+			log.Printf("Warning: failed to register default subscriber: %v", err)
+		}
+	}
+
+	// Load custom rules; invalid conditions are reported here, not at evaluation time
+	var ruleEngine *rules.Engine
+	if cfg.RulesSource != "" {
+		var source rules.Source
+		var changes <-chan struct{}
+
+		switch cfg.RulesSource {
+		case "store":
+			if notifier, ok := store.(storage.RuleChangeNotifier); ok {
+				if changes, err = notifier.ListenRuleChanges(ctx); err != nil {
+					log.Printf("Warning: rule change notifications unavailable, relying on polling: %v", err)
+				}
+			}
+			source = store
+		default:
+			source = rules.FileSource{Path: cfg.RulesFile}
+		}
+
+		ruleEngine = rules.NewEngine()
+		reloader := rules.NewReloader(source, ruleEngine,
+			time.Duration(cfg.RulesReloadSeconds)*time.Second, changes)
+		if err := reloader.Reload(ctx); err != nil {
+			log.Fatalf("Failed to load rules: %v", err)
+		}
+		go reloader.Run(ctx)
+		log.Printf("  Custom rules: %s source, reloading every %ds", cfg.RulesSource, cfg.RulesReloadSeconds)
+	}
+
+	// Track positions for SELL filtering, scale-in detection and P&L lines
+	var positions *portfolio.Book
+	if cfg.PositionsSource != "" {
+		positions = portfolio.NewBook()
+		if cfg.PositionsSource == "file" {
+			loader := portfolio.NewFileLoader(cfg.PositionsFile, positions,
+				time.Duration(cfg.PositionsReloadSeconds)*time.Second)
+			if err := loader.Reload(); err != nil {
+				log.Fatalf("Failed to load positions: %v", err)
+			}
+			go loader.Run(ctx)
+			log.Printf("  Positions: %s, reloading every %ds", cfg.PositionsFile, cfg.PositionsReloadSeconds)
+		} else {
+			log.Printf("  Positions topic: %s", cfg.KafkaPositionTopic)
+		}
+	}
+
+	// Create notification dispatcher
+	notifiers := []notify.Notifier{telegramClient}
+	if cfg.SMTPHost != "" {
+		notifiers = append(notifiers, email.NewClient(email.Config{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.EmailFrom,
+			To:       cfg.EmailTo,
+		}))
+		log.Printf("  Email: %s via %s:%d", strings.Join(cfg.EmailTo, ", "), cfg.SMTPHost, cfg.SMTPPort)
+	}
+	notifier := notify.NewDispatcher(notifiers...)
+
 	// Create alert service
-	alertService := service.NewAlertService(cfg, telegramClient)
+	alertService := service.NewAlertService(cfg, notifier, marketState, ruleEngine, store)
+	go alertService.RunCooldownExpiry(ctx, time.Hour)
+	if positions != nil {
+		alertService.SetPositions(positions)
+	}
+	if cfg.SignalTagsFile != "" {
+		classifier, err := classify.LoadFile(cfg.SignalTagsFile)
+		if err != nil {
+			log.Fatalf("Failed to load signal tags: %v", err)
+		}
+		alertService.SetClassifier(classifier)
+		log.Printf("  Signal tags: %d from %s", len(classifier.Tags()), cfg.SignalTagsFile)
+	}
+
+	// Skip recipients that are in quiet hours
+	telegramClient.SetRecipients(alertService.AlertRecipients)
+	if cfg.QuietHours != "" {
+		log.Printf("  Quiet hours: %s (%s)", cfg.QuietHours, cfg.QuietHoursTimezone)
+	}
+	go alertService.RunDigest(ctx, time.Minute)
+	go alertService.RunChannelDigests(ctx)
+	go alertService.RunReports(ctx)
+	go alertService.RunRankingRetention(ctx, time.Hour)
+
+	// Answer bot commands such as /detail
+	if cfg.TelegramCommands {
+		bot := telegram.NewBot(telegramClient)
+		bot.SetAuthorizer(alertService.IsSubscriber)
+		bot.Handle("detail", "show the full alert for a digest entry: /detail <id>", alertService.DetailCommand)
+		bot.Handle("watchlist", "list or edit watchlists: /watchlist [add|remove|delete|on|off] <name> [symbols]", alertService.WatchlistCommand)
+		bot.Handle("hitrate", "signal hit rates by confidence and rule: /hitrate [days]", alertService.HitRateCommand)
+		bot.Handle("mute", "list mutes or mute a symbol: /mute [<symbol> [duration] [reason]]", alertService.MuteCommand)
+		bot.Handle("unmute", "unmute a symbol: /unmute <symbol>", alertService.UnmuteCommand)
+		go bot.Run(ctx)
+	}
+
+	// Start admin server
+	var adminServer *admin.Server
+	if cfg.AdminAddr != "" {
+		adminServer = admin.NewServer(cfg.AdminAddr)
+		adminServer.HandleJSON("/status", func(r *http.Request) (interface{}, error) {
+			return alertService.Status(), nil
+		})
+		adminServer.HandleJSON("/watchlists", func(r *http.Request) (interface{}, error) {
+			return alertService.Watchlists(), nil
+		})
+		adminServer.HandleJSON("/mutes", func(r *http.Request) (interface{}, error) {
+			return alertService.Mutes(r.Context())
+		})
+		adminServer.HandleJSON("/rankings", func(r *http.Request) (interface{}, error) {
+			q := r.URL.Query()
+			if q.Get("symbol") == "" {
+				return nil, admin.BadRequest("symbol is required")
+			}
+			signal := strings.ToUpper(q.Get("signal"))
+			if signal == "" {
+				signal = models.SignalBuy
+			}
+			days, err := strconv.Atoi(q.Get("days"))
+			if err != nil || days <= 0 {
+				days = 30
+			}
+			return alertService.RankTimeline(r.Context(), signal, q.Get("symbol"), time.Now().AddDate(0, 0, -days))
+		})
+		adminServer.HandleJSON("/outcomes", func(r *http.Request) (interface{}, error) {
+			days, err := strconv.Atoi(r.URL.Query().Get("days"))
+			if err != nil || days <= 0 {
+				days = 30
+			}
+			alertedOnly, _ := strconv.ParseBool(r.URL.Query().Get("alerted"))
+			now := time.Now()
+			return alertService.HitRates(r.Context(), now.AddDate(0, 0, -days), now, alertedOnly)
+		})
+		adminServer.Start()
+		log.Printf("  Admin server: %s", cfg.AdminAddr)
+	}
 
 	// Create Kafka consumer
-	consumer, err := kafka.NewConsumer(
-		cfg.KafkaBrokers,
-		cfg.KafkaConsumerGroup,
-		cfg.KafkaDecisionTopic,
-		cfg.KafkaRankingTopic,
-	)
+	topics := kafka.Topics{
+		Decision: cfg.KafkaDecisionTopic,
+		Ranking:  cfg.KafkaRankingTopic,
+	}
+	if cfg.EnableMarketData {
+		topics.Quote = cfg.KafkaQuoteTopic
+		topics.Indicator = cfg.KafkaIndicatorTopic
+	}
+	if cfg.PositionsSource == "topic" {
+		topics.Position = cfg.KafkaPositionTopic
+	}
+	consumer, err := kafka.NewConsumer(cfg.KafkaBrokers, cfg.KafkaConsumerGroup, topics)
 	if err != nil {
 		log.Fatalf("Failed to create Kafka consumer: %v", err)
 	}
@@ -52,10 +233,9 @@ func main() {
 	// Set up handlers
 	consumer.SetDecisionHandler(alertService.HandleDecisionEvent)
 	consumer.SetRankingHandler(alertService.HandleRankingEvent)
-
-	// Create context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	consumer.SetQuoteHandler(alertService.HandleQuoteEvent)
+	consumer.SetIndicatorHandler(alertService.HandleIndicatorEvent)
+	consumer.SetPositionHandler(alertService.HandlePositionEvent)
 
 	// Start consumer
 	if err := consumer.Start(ctx); err != nil {
@@ -78,6 +258,14 @@ func main() {
 	log.Println("Shutting down alert-service...")
 	cancel()
 
+	if adminServer != nil {
+		shutdownAdminCtx, cancelAdmin := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := adminServer.Shutdown(shutdownAdminCtx); err != nil {
+			log.Printf("Warning: admin server shutdown: %v", err)
+		}
+		cancelAdmin()
+	}
+
 	// Send shutdown notification
 	shutdownCtx := context.Background()
 	shutdownMsg := "🛑 <b>Alert Service Stopped</b>"
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/storage"
+	"github.com/trogers1052/alert-service/internal/storage/postgres"
+	"github.com/trogers1052/alert-service/internal/storage/sqlite"
+)
+
+// openStore opens and migrates the configured storage backend.
+// It returns nil when persistence is disabled.
+func openStore(ctx context.Context, cfg *config.Config) (storage.Store, error) {
+	var store storage.Store
+
+	switch cfg.StorageBackend {
+	case "":
+		return nil, nil
+
+	case "postgres":
+		pg, err := postgres.Open(ctx, postgres.Config{
+			Host:     cfg.DBHost,
+			Port:     cfg.DBPort,
+			User:     cfg.DBUser,
+			Password: cfg.DBPassword,
+			DBName:   cfg.DBName,
+			SSLMode:  cfg.DBSSLMode,
+		})
+		if err != nil {
+			return nil, err
+		}
+		store = pg
+		log.Printf("  Storage: postgres %s:%d/%s", cfg.DBHost, cfg.DBPort, cfg.DBName)
+
+	case "sqlite":
+		lite, err := sqlite.Open(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		store = lite
+		log.Printf("  Storage: sqlite %s", cfg.SQLitePath)
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to migrate %s storage: %w", cfg.StorageBackend, err)
+	}
+	return store, nil
+}
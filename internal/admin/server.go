@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Server is a small HTTP server for health checks and operational status
+type Server struct {
+	mux *http.ServeMux
+	srv *http.Server
+}
+
+// NewServer creates an admin server listening on addr, e.g. ":8080"
+func NewServer(addr string) *Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+
+	return &Server{
+		mux: mux,
+		srv: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// BadRequestError reports an invalid request. HandleJSON answers it with
+// 400 Bad Request instead of 500.
+type BadRequestError struct {
+	Message string
+}
+
+func (e *BadRequestError) Error() string {
+	return e.Message
+}
+
+// BadRequest returns a BadRequestError with a formatted message
+func BadRequest(format string, args ...interface{}) error {
+	return &BadRequestError{Message: fmt.Sprintf(format, args...)}
+}
+
+// HandleJSON serves the value returned by fn as JSON at path
+func (s *Server) HandleJSON(path string, fn func(r *http.Request) (interface{}, error)) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		v, err := fn(r)
+		var bad *BadRequestError
+		if errors.As(err, &bad) {
+			http.Error(w, bad.Message, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("Admin request %s failed: %v", r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			log.Printf("Failed to write admin response for %s: %v", r.URL.Path, err)
+		}
+	})
+}
+
+// Start serves requests in the background
+func (s *Server) Start() {
+	go func() {
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Admin server stopped: %v", err)
+		}
+	}()
+}
+
+// Shutdown stops the server, waiting for in-flight requests
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
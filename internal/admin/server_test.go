@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		fn       func(r *http.Request) (interface{}, error)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "value",
+			method:   http.MethodGet,
+			fn:       func(r *http.Request) (interface{}, error) { return map[string]int{"n": 1}, nil },
+			wantCode: http.StatusOK,
+			wantBody: `"n": 1`,
+		},
+		{
+			name:     "bad request",
+			method:   http.MethodGet,
+			fn:       func(r *http.Request) (interface{}, error) { return nil, BadRequest("symbol is required") },
+			wantCode: http.StatusBadRequest,
+			wantBody: "symbol is required",
+		},
+		{
+			name:     "internal error",
+			method:   http.MethodGet,
+			fn:       func(r *http.Request) (interface{}, error) { return nil, errors.New("store down") },
+			wantCode: http.StatusInternalServerError,
+			wantBody: "store down",
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodPost,
+			fn:       func(r *http.Request) (interface{}, error) { return nil, nil },
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer("")
+			s.HandleJSON("/test", tt.fn)
+
+			rec := httptest.NewRecorder()
+			s.mux.ServeHTTP(rec, httptest.NewRequest(tt.method, "/test", nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package calendar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed nyse.json
+var nyseData []byte
+
+// Session is a US equity market session
+type Session string
+
+// Market sessions
+const (
+	PreMarket  Session = "premarket"
+	Regular    Session = "regular"
+	AfterHours Session = "afterhours"
+	Closed     Session = "closed"
+)
+
+// Session boundaries in minutes after midnight, exchange time
+const (
+	preMarketOpen   = 4 * 60
+	regularOpen     = 9*60 + 30
+	regularClose    = 16 * 60
+	afterHoursClose = 20 * 60
+	// After-hours trading ends this long after an early close
+	earlyAfterHours = 4 * 60
+)
+
+// Label returns a human-readable session name for messages
+func (s Session) Label() string {
+	switch s {
+	case PreMarket:
+		return "Pre-market"
+	case Regular:
+		return "Regular hours"
+	case AfterHours:
+		return "After-hours"
+	default:
+		return "Market closed"
+	}
+}
+
+// Calendar is an exchange trading calendar with holidays and early closes
+type Calendar struct {
+	loc         *time.Location
+	holidays    map[string]string // date -> holiday name
+	earlyCloses map[string]int    // date -> close in minutes after midnight
+	firstYear   int
+	lastYear    int
+}
+
+type calendarFile struct {
+	Holidays    map[string]string `json:"holidays"`
+	EarlyCloses map[string]string `json:"early_closes"`
+	FirstYear   int               `json:"first_year"`
+	LastYear    int               `json:"last_year"`
+}
+
+var nyse = mustLoad(nyseData, "America/New_York")
+
+// NYSE returns the embedded NYSE/NASDAQ calendar
+func NYSE() *Calendar {
+	return nyse
+}
+
+func mustLoad(raw []byte, zone string) *Calendar {
+	c, err := load(raw, zone)
+	if err != nil {
+		panic(fmt.Sprintf("calendar: invalid embedded data: %v", err))
+	}
+	return c
+}
+
+func load(raw []byte, zone string) (*Calendar, error) {
+	var f calendarFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Calendar{
+		loc:         loc,
+		holidays:    f.Holidays,
+		earlyCloses: make(map[string]int, len(f.EarlyCloses)),
+		firstYear:   f.FirstYear,
+		lastYear:    f.LastYear,
+	}
+	for date, clock := range f.EarlyCloses {
+		hh, mm, _ := strings.Cut(clock, ":")
+		h, err1 := strconv.Atoi(hh)
+		m, err2 := strconv.Atoi(mm)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("early close %s: invalid time %q", date, clock)
+		}
+		c.earlyCloses[date] = h*60 + m
+	}
+	return c, nil
+}
+
+// Location returns the exchange time zone
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+// Covers reports whether holidays are known for t's year. Outside the
+// covered years only weekends are treated as non-trading days.
+func (c *Calendar) Covers(t time.Time) bool {
+	year := t.In(c.loc).Year()
+	return year >= c.firstYear && year <= c.lastYear
+}
+
+// Holiday returns the name of the exchange holiday on t's date, if any
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	name, ok := c.holidays[t.In(c.loc).Format("2006-01-02")]
+	return name, ok
+}
+
+// IsTradingDay reports whether the exchange is open on t's date
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	local := t.In(c.loc)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c.Holiday(local)
+	return !holiday
+}
+
+// AddTradingDays returns the same clock time n trading days after t
+func (c *Calendar) AddTradingDays(t time.Time, n int) time.Time {
+	local := t.In(c.loc)
+	for n > 0 {
+		local = local.AddDate(0, 0, 1)
+		if c.IsTradingDay(local) {
+			n--
+		}
+	}
+	return local
+}
+
+// IsEarlyClose reports whether the regular session closes early on t's date
+func (c *Calendar) IsEarlyClose(t time.Time) bool {
+	_, ok := c.earlyCloses[t.In(c.loc).Format("2006-01-02")]
+	return ok
+}
+
+// Session returns the market session at t
+func (c *Calendar) Session(t time.Time) Session {
+	if !c.IsTradingDay(t) {
+		return Closed
+	}
+
+	local := t.In(c.loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	closeAt, afterClose := regularClose, afterHoursClose
+	if early, ok := c.earlyCloses[local.Format("2006-01-02")]; ok {
+		closeAt, afterClose = early, early+earlyAfterHours
+	}
+
+	switch {
+	case minute < preMarketOpen:
+		return Closed
+	case minute < regularOpen:
+		return PreMarket
+	case minute < closeAt:
+		return Regular
+	case minute < afterClose:
+		return AfterHours
+	default:
+		return Closed
+	}
+}
@@ -0,0 +1,160 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newYork(t *testing.T, value string) time.Time {
+	t.Helper()
+	at, err := time.ParseInLocation("2006-01-02 15:04", value, NYSE().Location())
+	if err != nil {
+		t.Fatalf("bad test time %q: %v", value, err)
+	}
+	return at
+}
+
+func TestSession(t *testing.T) {
+	// Each day is walked through its session boundaries, "HH:MM=session"
+	days := map[string]string{
+		"2026-03-02": "03:59=closed 04:00=premarket 09:29=premarket 09:30=regular 15:59=regular 16:00=afterhours 19:59=afterhours 20:00=closed",
+		"2026-03-07": "12:00=closed",                                                 // saturday
+		"2026-03-08": "12:00=closed",                                                 // sunday
+		"2026-03-09": "09:30=regular",                                                // after daylight saving starts
+		"2026-04-03": "12:00=closed",                                                 // good friday
+		"2026-07-03": "10:00=closed",                                                 // observed independence day
+		"2025-01-09": "10:00=closed",                                                 // one-off closure
+		"2026-11-27": "12:59=regular 13:00=afterhours 16:59=afterhours 17:00=closed", // early close
+		"2026-12-24": "13:30=afterhours",                                             // christmas eve
+		"2028-01-03": "10:00=regular",                                                // uncovered year
+		"2028-12-25": "10:00=regular",                                                // uncovered year holiday
+	}
+
+	for day, timeline := range days {
+		for _, point := range strings.Fields(timeline) {
+			clock, want, _ := strings.Cut(point, "=")
+			if got := NYSE().Session(newYork(t, day+" "+clock)); got != Session(want) {
+				t.Errorf("Session(%s %s) = %s, want %s", day, clock, got, want)
+			}
+		}
+	}
+}
+
+func TestSessionConvertsToExchangeTime(t *testing.T) {
+	// 14:00 UTC is 09:00 in New York in winter and 10:00 in summer
+	tests := []struct {
+		at   time.Time
+		want Session
+	}{
+		{time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC), PreMarket},
+		{time.Date(2026, 7, 6, 14, 0, 0, 0, time.UTC), Regular},
+		{time.Date(2026, 3, 7, 0, 30, 0, 0, time.UTC), AfterHours}, // Friday evening in New York
+	}
+
+	for _, tt := range tests {
+		if got := NYSE().Session(tt.at); got != tt.want {
+			t.Errorf("Session(%s) = %s, want %s", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestTradingDays(t *testing.T) {
+	tests := []struct {
+		at      string
+		trading bool
+		holiday string
+		early   bool
+		covered bool
+	}{
+		{at: "2026-03-02 12:00", trading: true, covered: true},
+		{at: "2026-03-07 12:00", covered: true},
+		{at: "2026-11-26 12:00", holiday: "Thanksgiving Day", covered: true},
+		{at: "2026-11-27 12:00", trading: true, early: true, covered: true},
+		{at: "2027-06-18 12:00", holiday: "Juneteenth (observed)", covered: true},
+		{at: "2024-07-03 12:00", trading: true, early: true, covered: true},
+		{at: "2023-12-25 12:00", trading: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.at, func(t *testing.T) {
+			at := newYork(t, tt.at)
+			cal := NYSE()
+			if got := cal.IsTradingDay(at); got != tt.trading {
+				t.Errorf("IsTradingDay() = %v, want %v", got, tt.trading)
+			}
+			if got, _ := cal.Holiday(at); got != tt.holiday {
+				t.Errorf("Holiday() = %q, want %q", got, tt.holiday)
+			}
+			if got := cal.IsEarlyClose(at); got != tt.early {
+				t.Errorf("IsEarlyClose() = %v, want %v", got, tt.early)
+			}
+			if got := cal.Covers(at); got != tt.covered {
+				t.Errorf("Covers() = %v, want %v", got, tt.covered)
+			}
+		})
+	}
+}
+
+func TestAddTradingDays(t *testing.T) {
+	tests := []struct {
+		from string
+		n    int
+		want string
+	}{
+		{"2026-03-02 16:00", 0, "2026-03-02 16:00"},
+		{"2026-03-02 16:00", 1, "2026-03-03 16:00"},
+		{"2026-03-06 16:00", 1, "2026-03-09 16:00"}, // over a weekend
+		{"2026-04-02 16:00", 1, "2026-04-06 16:00"}, // over Good Friday
+		{"2026-03-06 10:00", 5, "2026-03-13 10:00"}, // across the daylight saving change
+		{"2026-11-25 16:00", 2, "2026-11-30 16:00"}, // over Thanksgiving
+		{"2026-03-07 12:00", 1, "2026-03-09 12:00"}, // from a weekend
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			got := NYSE().AddTradingDays(newYork(t, tt.from), tt.n)
+			if want := newYork(t, tt.want); !got.Equal(want) {
+				t.Errorf("AddTradingDays(%s, %d) = %s, want %s", tt.from, tt.n, got, want)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	filter, err := ParseFilter("watch=regular; RANKINGS = trading_day ;SELL=premarket,afterhours")
+	if err != nil {
+		t.Fatalf("ParseFilter() error = %v", err)
+	}
+
+	tests := []struct {
+		kind string
+		at   string
+		want bool
+	}{
+		{"WATCH", "2026-03-02 10:00", true},
+		{"WATCH", "2026-03-02 17:00", false},
+		{"RANKINGS", "2026-03-02 22:00", true},
+		{"RANKINGS", "2026-03-07 10:00", false},
+		{"SELL", "2026-03-02 08:00", true},
+		{"SELL", "2026-03-02 10:00", false},
+		{"SELL", "2026-11-27 14:00", true},
+		{"BUY", "2026-03-07 10:00", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+" "+tt.at, func(t *testing.T) {
+			if got := filter.Allows(NYSE(), tt.kind, newYork(t, tt.at)); got != tt.want {
+				t.Errorf("Allows(%s, %s) = %v, want %v", tt.kind, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, spec := range []string{"regular", "=regular", "WATCH=lunch", "WATCH=regular,"} {
+		if _, err := ParseFilter(spec); err == nil {
+			t.Errorf("ParseFilter(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TradingDay matches any time on a trading day, whatever the session
+const TradingDay = "trading_day"
+
+// Filter restricts alert kinds to market sessions. Keys are signals (BUY,
+// SELL, WATCH) or RANKINGS and RULES; kinds without an entry are unrestricted.
+type Filter map[string][]string
+
+// ParseFilter parses entries like "WATCH=regular;RANKINGS=trading_day"
+// where each value is a comma-separated list of sessions or trading_day
+func ParseFilter(spec string) (Filter, error) {
+	f := make(Filter)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kind, sessions, ok := strings.Cut(entry, "=")
+		kind = strings.ToUpper(strings.TrimSpace(kind))
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("session filter %q: want KIND=sessions", entry)
+		}
+
+		for _, name := range strings.Split(sessions, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if !validSession(name) {
+				return nil, fmt.Errorf("session filter %q: unknown session %q", entry, name)
+			}
+			f[kind] = append(f[kind], name)
+		}
+	}
+	return f, nil
+}
+
+// validSession reports whether name is a session or trading_day
+func validSession(name string) bool {
+	switch Session(name) {
+	case PreMarket, Regular, AfterHours, Closed:
+		return true
+	}
+	return name == TradingDay
+}
+
+// Allows reports whether an alert of the given kind may be sent at t
+func (f Filter) Allows(c *Calendar, kind string, t time.Time) bool {
+	sessions, ok := f[strings.ToUpper(kind)]
+	if !ok {
+		return true
+	}
+
+	current := c.Session(t)
+	for _, name := range sessions {
+		if name == TradingDay && c.IsTradingDay(t) {
+			return true
+		}
+		if Session(name) == current {
+			return true
+		}
+	}
+	return false
+}
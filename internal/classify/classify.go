@@ -0,0 +1,259 @@
+package classify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+// Alert priorities. High priority alerts break through quiet hours and
+// channel digests; low priority alerts go to the low-priority channels.
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// ScaleIn is the tag of BUYs that add to an existing position
+const ScaleIn = "scale-in"
+
+// Tag classifies the decisions matching any of its rule name, metadata or
+// reasoning patterns. Patterns are case-insensitive regular expressions.
+type Tag struct {
+	Name      string            `json:"name"`
+	Signals   []string          `json:"signals,omitempty"`   // signals the tag applies to; empty for all
+	Rules     []string          `json:"rules,omitempty"`     // patterns matched against triggered rule names
+	Metadata  map[string]string `json:"metadata,omitempty"`  // metadata key -> value pattern; "" matches any value
+	Reasoning []string          `json:"reasoning,omitempty"` // patterns matched against the primary and rule reasoning
+
+	Label    string   `json:"label,omitempty"`    // replaces the signal in the alert header, e.g. SCALE-IN
+	Emoji    string   `json:"emoji,omitempty"`    // replaces the signal emoji
+	Priority string   `json:"priority,omitempty"` // high, normal or low
+	Channels []string `json:"channels,omitempty"` // channels the alert goes to instead of the usual ones
+}
+
+// DefaultScaleIn is the built-in scale-in tag, matching the rule names and
+// reasoning of averaging down
+var DefaultScaleIn = Tag{
+	Name:      ScaleIn,
+	Signals:   []string{models.SignalBuy},
+	Rules:     []string{`average down`},
+	Reasoning: []string{`average down`, `scale[- ]in`, `adding to position`},
+	Label:     "SCALE-IN",
+	Emoji:     "📈",
+}
+
+type compiledTag struct {
+	Tag
+	rules     []*regexp.Regexp
+	metadata  map[string]*regexp.Regexp
+	reasoning []*regexp.Regexp
+}
+
+// Classifier tags decisions. Tags are checked in order.
+type Classifier struct {
+	tags []compiledTag
+}
+
+// Default returns a classifier with the built-in tags
+func Default() *Classifier {
+	c, _ := New([]Tag{DefaultScaleIn})
+	return c
+}
+
+// New compiles a classifier from tags
+func New(tags []Tag) (*Classifier, error) {
+	c := &Classifier{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag.Name = strings.ToLower(strings.TrimSpace(tag.Name))
+		if tag.Name == "" {
+			return nil, fmt.Errorf("tag without a name")
+		}
+		if seen[tag.Name] {
+			return nil, fmt.Errorf("tag %q is defined twice", tag.Name)
+		}
+		seen[tag.Name] = true
+
+		tag.Priority = strings.ToLower(tag.Priority)
+		switch tag.Priority {
+		case "", PriorityHigh, PriorityNormal, PriorityLow:
+		default:
+			return nil, fmt.Errorf("tag %q: priority must be high, normal or low, got %q", tag.Name, tag.Priority)
+		}
+		tag.Signals = mapStrings(tag.Signals, strings.ToUpper)
+		tag.Channels = mapStrings(tag.Channels, strings.ToLower)
+
+		compiled := compiledTag{Tag: tag, metadata: make(map[string]*regexp.Regexp)}
+		var err error
+		if compiled.rules, err = compileAll(tag.Name, tag.Rules); err != nil {
+			return nil, err
+		}
+		if compiled.reasoning, err = compileAll(tag.Name, tag.Reasoning); err != nil {
+			return nil, err
+		}
+		for key, pattern := range tag.Metadata {
+			if compiled.metadata[key], err = compile(tag.Name, pattern); err != nil {
+				return nil, err
+			}
+		}
+		if len(compiled.rules) == 0 && len(compiled.metadata) == 0 && len(compiled.reasoning) == 0 {
+			return nil, fmt.Errorf("tag %q has no rules, metadata or reasoning patterns", tag.Name)
+		}
+		c.tags = append(c.tags, compiled)
+	}
+	return c, nil
+}
+
+func compile(tag, pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("tag %q: invalid pattern %q: %w", tag, pattern, err)
+	}
+	return re, nil
+}
+
+func compileAll(tag string, patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := compile(tag, pattern)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// LoadFile reads a classifier from a JSON file with a "tags" list
+func LoadFile(path string) (*Classifier, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signal tags file: %w", err)
+	}
+
+	var file struct {
+		Tags []Tag `json:"tags"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse signal tags file %s: %w", path, err)
+	}
+	c, err := New(file.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signal tags file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Classify returns the tags matching a decision, in classifier order
+func (c *Classifier) Classify(data *models.DecisionData) []Tag {
+	var tags []Tag
+	for _, t := range c.tags {
+		if t.matches(data) {
+			tags = append(tags, t.Tag)
+		}
+	}
+	return tags
+}
+
+// Tags returns the classifier's tags in order
+func (c *Classifier) Tags() []Tag {
+	tags := make([]Tag, len(c.tags))
+	for i, t := range c.tags {
+		tags[i] = t.Tag
+	}
+	return tags
+}
+
+// Lookup returns a tag by name
+func (c *Classifier) Lookup(name string) (Tag, bool) {
+	for _, t := range c.tags {
+		if t.Name == name {
+			return t.Tag, true
+		}
+	}
+	return Tag{}, false
+}
+
+func (t *compiledTag) matches(data *models.DecisionData) bool {
+	if len(t.Signals) > 0 && !contains(t.Signals, data.Signal) {
+		return false
+	}
+
+	for _, re := range t.rules {
+		for _, rule := range data.RulesTriggered {
+			if re.MatchString(rule.RuleName) {
+				return true
+			}
+		}
+	}
+
+	for key, re := range t.metadata {
+		if value, ok := data.Metadata[key]; ok && value != nil && re.MatchString(fmt.Sprint(value)) {
+			return true
+		}
+	}
+
+	for _, re := range t.reasoning {
+		if re.MatchString(data.PrimaryReasoning) {
+			return true
+		}
+		for _, rule := range data.RulesTriggered {
+			if re.MatchString(rule.Reasoning) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Has reports whether a tag is among tags
+func Has(tags []Tag, name string) bool {
+	for _, t := range tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Priority returns high if any tag is high priority, otherwise low if any
+// tag is low priority, otherwise normal
+func Priority(tags []Tag) string {
+	priority := PriorityNormal
+	for _, t := range tags {
+		switch t.Priority {
+		case PriorityHigh:
+			return PriorityHigh
+		case PriorityLow:
+			priority = PriorityLow
+		}
+	}
+	return priority
+}
+
+// mapStrings returns a copy of values with fn applied, leaving the
+// caller's slice untouched
+func mapStrings(values []string, fn func(string) string) []string {
+	if values == nil {
+		return nil
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = fn(strings.TrimSpace(v))
+	}
+	return out
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package classify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+func TestClassify(t *testing.T) {
+	c, err := LoadFile("../../signal_tags.example.json")
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     models.DecisionData
+		tags     []string
+		priority string
+	}{
+		{
+			name: "rule name",
+			data: models.DecisionData{Signal: "BUY", RulesTriggered: []models.RuleResult{{RuleName: "Average Down Setup"}}},
+			tags: []string{"scale-in"}, priority: PriorityNormal,
+		},
+		{
+			name: "reasoning",
+			data: models.DecisionData{Signal: "BUY", PrimaryReasoning: "Good spot to scale in"},
+			tags: []string{"scale-in"}, priority: PriorityNormal,
+		},
+		{
+			name: "signal filter",
+			data: models.DecisionData{Signal: "SELL", PrimaryReasoning: "scale in"},
+			tags: nil, priority: PriorityNormal,
+		},
+		{
+			name: "metadata",
+			data: models.DecisionData{Signal: "SELL", Metadata: map[string]interface{}{"stop_hit": true}},
+			tags: []string{"stop-loss"}, priority: PriorityHigh,
+		},
+		{
+			name: "metadata pattern is anchored",
+			data: models.DecisionData{Signal: "BUY", Metadata: map[string]interface{}{"setup": "failed breakout"}},
+			tags: nil, priority: PriorityNormal,
+		},
+		{
+			name: "several tags in file order",
+			data: models.DecisionData{Signal: "BUY", PrimaryReasoning: "adding to position",
+				RulesTriggered: []models.RuleResult{{RuleName: "breakout"}}},
+			tags: []string{"scale-in", "breakout"}, priority: PriorityNormal,
+		},
+		{
+			name: "low priority",
+			data: models.DecisionData{Signal: "WATCH", PrimaryReasoning: "Low volume drift"},
+			tags: []string{"watch-only"}, priority: PriorityLow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Classify(&tt.data)
+			var names []string
+			for _, tag := range got {
+				names = append(names, tag.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.tags, ",") {
+				t.Errorf("Classify() = %v, want %v", names, tt.tags)
+			}
+			if p := Priority(got); p != tt.priority {
+				t.Errorf("Priority() = %q, want %q", p, tt.priority)
+			}
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []Tag
+		want string
+	}{
+		{"no name", []Tag{{Rules: []string{"x"}}}, "without a name"},
+		{"duplicate", []Tag{{Name: "a", Rules: []string{"x"}}, {Name: "A", Rules: []string{"y"}}}, "defined twice"},
+		{"bad priority", []Tag{{Name: "a", Rules: []string{"x"}, Priority: "urgent"}}, "priority"},
+		{"no patterns", []Tag{{Name: "a"}}, "no rules"},
+		{"bad pattern", []Tag{{Name: "a", Reasoning: []string{"("}}}, "invalid pattern"},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.tags); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: New() error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
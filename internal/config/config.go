@@ -5,57 +5,399 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/trogers1052/alert-service/internal/calendar"
+	"github.com/trogers1052/alert-service/internal/schedule"
+	"github.com/trogers1052/alert-service/internal/watchlist"
 )
 
 // Config holds all configuration for the alert service
 type Config struct {
 	// Kafka
-	KafkaBrokers       []string
-	KafkaConsumerGroup string
-	KafkaDecisionTopic string // trading.decisions from decision-engine
-	KafkaRankingTopic  string // trading.rankings from decision-engine
+	KafkaBrokers        []string
+	KafkaConsumerGroup  string
+	KafkaDecisionTopic  string // trading.decisions from decision-engine
+	KafkaRankingTopic   string // trading.rankings from decision-engine
+	KafkaQuoteTopic     string // stock.quotes.realtime price updates
+	KafkaIndicatorTopic string // stock.indicators technical indicator updates
+	KafkaPositionTopic  string // trading.positions position updates
+
+	// Market data
+	EnableMarketData     bool // Consume quote and indicator topics
+	MarketDataMaxAgeSecs int  // Age after which a symbol's quote is considered stale
+
+	// Custom rules
+	RulesSource        string // "file", "store" (the storage backend) or empty to disable custom rules
+	RulesFile          string // JSON file of custom alert rules when RulesSource is "file"
+	RulesReloadSeconds int    // Poll interval for rule changes (0 disables polling)
+
+	// Positions
+	PositionsSource        string // "file", "topic" or empty to ignore positions
+	PositionsFile          string // JSON file of positions when PositionsSource is "file"
+	PositionsReloadSeconds int    // Poll interval for positions file changes (0 disables polling)
+	PositionsFilterSells   bool   // Only alert SELL signals for held symbols
+
+	// Signal classification
+	SignalTagsFile string // JSON file of signal tags; empty uses the built-in scale-in tag
+
+	// Decision rule filters; rule names are matched case-insensitively
+	DecisionRulesRequire       []string           // Decisions need at least one of these triggered rules
+	DecisionRulesExclude       []string           // Decisions with any of these triggered rules are suppressed
+	DecisionRulesMinConfidence map[string]float64 // Per-rule minimum confidence, e.g. rsi_oversold:0.7; weaker rules do not count
+	DecisionRulesMinAgreeing   int                // Triggered rules that must count for an alert (0 disables)
+
+	// Signal reversals
+	FlipWindowMinutes int            // A BUY/SELL opposite to the symbol's last one within this window alerts as a reversal (0 disables)
+	FlipSymbolMinutes map[string]int // Per-symbol flip windows, e.g. TSLA:60
+
+	// Confidence trends
+	ConfidenceTrendPoints  int  // Confidence readings kept per symbol and signal (0 disables trends)
+	ConfidenceTrendHours   int  // Readings older than this are dropped (0 keeps them)
+	ConfidenceRisingStreak int  // Consecutive confidence increases that re-alert inside the cooldown (0 disables)
+	ConfidenceCrossing     bool // Re-alert inside the cooldown when confidence crosses MinConfidence from below
+
+	// Position sizing
+	SizingAccountSize        float64 // Account size used to size BUY alerts (0 disables sizing)
+	SizingRiskPercent        float64 // Percent of the account risked per trade
+	SizingStopIndicator      string  // Indicator the stop distance is derived from, e.g. atr_14
+	SizingStopMultiple       float64 // Stop distance as a multiple of the indicator
+	SizingMaxPositionPercent float64 // Cap on a position's value as a percent of the account (0 disables)
+
+	// Watchlists
+	Watchlists          string   // Named symbol lists, e.g. "metals=SLV,GLD;core-tech=AAPL,MSFT"; seeds the store
+	WatchlistChannels   string   // Channels per watchlist, e.g. "metals=email;core-tech=telegram,email"
+	WatchlistUnlisted   string   // Decisions for symbols on no active watchlist: "allow", "drop" or "route"
+	LowPriorityChannels []string // Channels for low-priority alerts: unlisted symbols with "route" and low-priority tags
+
+	// Storage
+	StorageBackend string // "postgres", "sqlite" or empty for no persistence
+	SQLitePath     string // Database file for the sqlite backend
+
+	// PostgreSQL
+	DBHost     string
+	DBPort     int
+	DBUser     string
+	DBPassword string
+	DBName     string
+	DBSSLMode  string
 
 	// Telegram
 	TelegramBotToken string
 	TelegramChatID   int64
+	TelegramCommands bool // Answer bot commands such as /detail
+
+	// Email (optional, enabled when SMTPHost is set)
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string
+	EmailTo      []string
+
+	// Digests
+	ChannelDigests string // Per-channel digest schedules, e.g. "email=every 60m;telegram=at 09:00,16:30"
+	DigestTimezone string // IANA zone for digest clock times
+
+	// Reports built from alert history
+	ReportEOD        string // End-of-day report recurrence, e.g. "mon-fri at 16:30"; skipped on market holidays
+	ReportWeekly     string // Weekly roll-up recurrence, e.g. "fri at 17:00"
+	ReportTimezone   string // IANA zone for report times and day boundaries
+	ReportTopSymbols int    // Number of most active symbols listed in reports
+	TrackOutcomes    bool   // Measure forward returns of BUY and SELL signals (needs a storage backend)
+
+	// Admin HTTP server
+	AdminAddr string // e.g. ":8080"; empty disables /healthz and /status
 
 	// Alert settings
-	MinConfidence     float64 // Minimum confidence to send alert
-	AlertOnBuy        bool    // Send alerts for BUY signals
-	AlertOnSell       bool    // Send alerts for SELL signals
-	AlertOnWatch      bool    // Send alerts for WATCH signals
-	AlertOnRankings   bool    // Send daily ranking summaries
-	RankingsTopN      int     // Number of top stocks to include in ranking alerts
-	CooldownMinutes   int     // Cooldown between alerts for same symbol
-	QuietHoursStart   int     // Hour to start quiet hours (0-23)
-	QuietHoursEnd     int     // Hour to end quiet hours (0-23)
-	EnableQuietHours  bool    // Whether to enable quiet hours
+	MinConfidence              float64        // Minimum confidence to send alert
+	AlertOnBuy                 bool           // Send alerts for BUY signals
+	AlertOnSell                bool           // Send alerts for SELL signals
+	AlertOnWatch               bool           // Send alerts for WATCH signals
+	AlertOnRankings            bool           // Send daily ranking summaries
+	RankingsTopN               int            // Number of top stocks to include in ranking alerts
+	RankingsDiff               bool           // Show only changes since the previous ranking
+	RankingsRankMove           int            // Report moves of more than this many ranks
+	RankingsScoreChange        float64        // Report score changes of at least this much (0 disables)
+	RankingsSkipUnchanged      bool           // Suppress ranking alerts when the top N did not change
+	RankingTimelineDays        int            // Days of rank history summarized in ranking alerts (0 disables)
+	RankingRetentionDays       int            // Days of ranking history kept in storage (0 keeps everything)
+	RankingFilterTopN          int            // Only alert decisions in the top N of the latest ranking (0 disables)
+	RankingFilterSignals       []string       // Signals the ranking filter applies to
+	CooldownMinutes            int            // Cooldown between alerts for same symbol
+	CooldownRetentionHours     int            // How long persisted cooldowns are kept
+	CooldownSignalMinutes      map[string]int // Per-signal cooldowns, e.g. SELL:10
+	CooldownSymbolMinutes      map[string]int // Per-symbol cooldowns, e.g. TSLA:90; wins over per-watchlist
+	CooldownWatchlistMinutes   map[string]int // Per-watchlist cooldowns, e.g. metals:120; wins over per-signal
+	CooldownDirectionBypass    bool           // Alert inside the cooldown when the symbol's signal changed
+	CooldownConfidenceJump     float64        // Alert inside the cooldown when confidence rose this much (0 disables)
+	CooldownAdaptive           bool           // Double the cooldown for symbols that keep re-alerting
+	CooldownAdaptiveWindow     int            // Minutes between alerts that still count as a repeat
+	CooldownAdaptiveMax        int            // Cap on the adaptive cooldown in minutes
+	QuietHoursStart            int            // Hour to start quiet hours (0-23)
+	QuietHoursEnd              int            // Hour to end quiet hours (0-23)
+	EnableQuietHours           bool           // Whether to enable quiet hours
+	AlertSessions              string         // Market sessions per alert kind, e.g. "WATCH=regular;RANKINGS=trading_day"
+	QuietHours                 string         // Weekly schedule, e.g. "mon-fri 22:00-07:00; sat,sun all"
+	QuietHoursTimezone         string         // IANA zone the schedule is evaluated in
+	QuietHoursDigest           bool           // Queue alerts during quiet hours and send a digest afterwards
+	QuietHoursSellBreakthrough float64        // SELL confidence that is sent despite quiet hours (0 disables)
 }
 
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	cfg := &Config{
 		// Kafka
-		KafkaBrokers:       strings.Split(getEnv("KAFKA_BROKERS", "localhost:19092"), ","),
-		KafkaConsumerGroup: getEnv("KAFKA_CONSUMER_GROUP", "alert-service"),
-		KafkaDecisionTopic: getEnv("KAFKA_DECISION_TOPIC", "trading.decisions"),
-		KafkaRankingTopic:  getEnv("KAFKA_RANKING_TOPIC", "trading.rankings"),
+		KafkaBrokers:        strings.Split(getEnv("KAFKA_BROKERS", "localhost:19092"), ","),
+		KafkaConsumerGroup:  getEnv("KAFKA_CONSUMER_GROUP", "alert-service"),
+		KafkaDecisionTopic:  getEnv("KAFKA_DECISION_TOPIC", "trading.decisions"),
+		KafkaRankingTopic:   getEnv("KAFKA_RANKING_TOPIC", "trading.rankings"),
+		KafkaQuoteTopic:     getEnv("KAFKA_PRICE_TOPIC", "stock.quotes.realtime"),
+		KafkaIndicatorTopic: getEnv("KAFKA_INDICATOR_TOPIC", "stock.indicators"),
+		KafkaPositionTopic:  getEnv("KAFKA_POSITIONS_TOPIC", "trading.positions"),
+
+		// Market data
+		EnableMarketData:     getEnvBool("ENABLE_MARKET_DATA", true),
+		MarketDataMaxAgeSecs: getEnvInt("MARKET_DATA_MAX_AGE_SECONDS", 300),
+
+		// Custom rules
+		RulesSource:        getEnv("RULES_SOURCE", ""),
+		RulesFile:          getEnv("RULES_FILE", ""),
+		RulesReloadSeconds: getEnvInt("RULES_RELOAD_SECONDS", 60),
+
+		// Positions
+		PositionsSource:        getEnv("POSITIONS_SOURCE", ""),
+		PositionsFile:          getEnv("POSITIONS_FILE", ""),
+		PositionsReloadSeconds: getEnvInt("POSITIONS_RELOAD_SECONDS", 60),
+		PositionsFilterSells:   getEnvBool("POSITIONS_FILTER_SELLS", true),
+
+		// Signal classification
+		SignalTagsFile: getEnv("SIGNAL_TAGS_FILE", ""),
+
+		// Decision rule filters
+		DecisionRulesRequire:     splitList(strings.ToLower(getEnv("DECISION_RULES_REQUIRE", ""))),
+		DecisionRulesExclude:     splitList(strings.ToLower(getEnv("DECISION_RULES_EXCLUDE", ""))),
+		DecisionRulesMinAgreeing: getEnvInt("DECISION_RULES_MIN_AGREEING", 0),
+
+		// Signal reversals
+		FlipWindowMinutes: getEnvInt("FLIP_WINDOW_MINUTES", 0),
+
+		// Confidence trends
+		ConfidenceTrendPoints:  getEnvInt("CONFIDENCE_TREND_POINTS", 8),
+		ConfidenceTrendHours:   getEnvInt("CONFIDENCE_TREND_HOURS", 24),
+		ConfidenceRisingStreak: getEnvInt("CONFIDENCE_RISING_STREAK", 0),
+		ConfidenceCrossing:     getEnvBool("CONFIDENCE_CROSSING", false),
+
+		// Position sizing
+		SizingAccountSize:        getEnvFloat("SIZING_ACCOUNT_SIZE", 0),
+		SizingRiskPercent:        getEnvFloat("SIZING_RISK_PERCENT", 1),
+		SizingStopIndicator:      strings.ToLower(getEnv("SIZING_STOP_INDICATOR", "atr_14")),
+		SizingStopMultiple:       getEnvFloat("SIZING_STOP_MULTIPLE", 2),
+		SizingMaxPositionPercent: getEnvFloat("SIZING_MAX_POSITION_PERCENT", 25),
+
+		// Watchlists
+		Watchlists:          getEnv("WATCHLISTS", ""),
+		WatchlistChannels:   getEnv("WATCHLIST_CHANNELS", ""),
+		WatchlistUnlisted:   strings.ToLower(getEnv("WATCHLIST_UNLISTED", "allow")),
+		LowPriorityChannels: splitList(strings.ToLower(getEnv("LOW_PRIORITY_CHANNELS", ""))),
+
+		// Storage
+		StorageBackend: getEnv("STORAGE_BACKEND", ""),
+		SQLitePath:     getEnv("SQLITE_PATH", "data/alerts.db"),
+
+		// PostgreSQL
+		DBHost:     getEnv("DB_HOST", "localhost"),
+		DBPort:     getEnvInt("DB_PORT", 5432),
+		DBUser:     getEnv("DB_USER", "trader"),
+		DBPassword: getEnv("DB_PASSWORD", ""),
+		DBName:     getEnv("DB_NAME", "trading_platform"),
+		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 
 		// Telegram
 		TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
 		TelegramChatID:   getEnvInt64("TELEGRAM_CHAT_ID", 0),
+		TelegramCommands: getEnvBool("TELEGRAM_COMMANDS", false),
+
+		// Email
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		EmailFrom:    getEnv("EMAIL_FROM", ""),
+		EmailTo:      splitList(getEnv("EMAIL_TO", "")),
+
+		// Digests
+		ChannelDigests: getEnv("CHANNEL_DIGESTS", ""),
+		DigestTimezone: getEnv("DIGEST_TZ", "America/New_York"),
+
+		// Reports
+		ReportEOD:        getEnv("REPORT_EOD", ""),
+		ReportWeekly:     getEnv("REPORT_WEEKLY", ""),
+		ReportTimezone:   getEnv("REPORT_TZ", "America/New_York"),
+		ReportTopSymbols: getEnvInt("REPORT_TOP_SYMBOLS", 5),
+		TrackOutcomes:    getEnvBool("TRACK_OUTCOMES", true),
+
+		// Admin HTTP server
+		AdminAddr: getEnv("ADMIN_ADDR", ""),
 
 		// Alert settings
-		MinConfidence:    getEnvFloat("MIN_CONFIDENCE", 0.6),
-		AlertOnBuy:       getEnvBool("ALERT_ON_BUY", true),
-		AlertOnSell:      getEnvBool("ALERT_ON_SELL", true),
-		AlertOnWatch:     getEnvBool("ALERT_ON_WATCH", false),
-		AlertOnRankings:  getEnvBool("ALERT_ON_RANKINGS", true),
-		RankingsTopN:     getEnvInt("RANKINGS_TOP_N", 5),
-		CooldownMinutes:  getEnvInt("COOLDOWN_MINUTES", 30),
-		QuietHoursStart:  getEnvInt("QUIET_HOURS_START", 22), // 10 PM
-		QuietHoursEnd:    getEnvInt("QUIET_HOURS_END", 7),    // 7 AM
-		EnableQuietHours: getEnvBool("ENABLE_QUIET_HOURS", false),
+		MinConfidence:              getEnvFloat("MIN_CONFIDENCE", 0.6),
+		AlertOnBuy:                 getEnvBool("ALERT_ON_BUY", true),
+		AlertOnSell:                getEnvBool("ALERT_ON_SELL", true),
+		AlertOnWatch:               getEnvBool("ALERT_ON_WATCH", false),
+		AlertOnRankings:            getEnvBool("ALERT_ON_RANKINGS", true),
+		RankingsTopN:               getEnvInt("RANKINGS_TOP_N", 5),
+		RankingsDiff:               getEnvBool("RANKINGS_DIFF", true),
+		RankingsRankMove:           getEnvInt("RANKINGS_RANK_MOVE", 2),
+		RankingsScoreChange:        getEnvFloat("RANKINGS_SCORE_CHANGE", 0.1),
+		RankingsSkipUnchanged:      getEnvBool("RANKINGS_SKIP_UNCHANGED", false),
+		RankingTimelineDays:        getEnvInt("RANKING_TIMELINE_DAYS", 3),
+		RankingRetentionDays:       getEnvInt("RANKING_RETENTION_DAYS", 90),
+		RankingFilterTopN:          getEnvInt("RANKING_FILTER_TOP_N", 0),
+		RankingFilterSignals:       splitList(strings.ToUpper(getEnv("RANKING_FILTER_SIGNALS", "BUY"))),
+		CooldownMinutes:            getEnvInt("COOLDOWN_MINUTES", 30),
+		CooldownRetentionHours:     getEnvInt("COOLDOWN_RETENTION_HOURS", 24),
+		CooldownDirectionBypass:    getEnvBool("COOLDOWN_DIRECTION_BYPASS", true),
+		CooldownConfidenceJump:     getEnvFloat("COOLDOWN_CONFIDENCE_JUMP", 0.15),
+		CooldownAdaptive:           getEnvBool("COOLDOWN_ADAPTIVE", false),
+		CooldownAdaptiveWindow:     getEnvInt("COOLDOWN_ADAPTIVE_WINDOW_MINUTES", 720),
+		CooldownAdaptiveMax:        getEnvInt("COOLDOWN_ADAPTIVE_MAX_MINUTES", 480),
+		QuietHoursStart:            getEnvInt("QUIET_HOURS_START", 22), // 10 PM
+		QuietHoursEnd:              getEnvInt("QUIET_HOURS_END", 7),    // 7 AM
+		EnableQuietHours:           getEnvBool("ENABLE_QUIET_HOURS", false),
+		AlertSessions:              getEnv("ALERT_SESSIONS", ""),
+		QuietHours:                 getEnv("QUIET_HOURS", ""),
+		QuietHoursTimezone:         getEnv("QUIET_HOURS_TZ", "UTC"),
+		QuietHoursDigest:           getEnvBool("QUIET_HOURS_DIGEST", true),
+		QuietHoursSellBreakthrough: getEnvFloat("QUIET_HOURS_SELL_BREAKTHROUGH", 0),
+	}
+
+	var err error
+	if cfg.CooldownSignalMinutes, err = getEnvMinutes("COOLDOWN_SIGNAL_MINUTES"); err != nil {
+		return nil, err
+	}
+	if cfg.CooldownSymbolMinutes, err = getEnvMinutes("COOLDOWN_SYMBOL_MINUTES"); err != nil {
+		return nil, err
+	}
+	if cfg.CooldownWatchlistMinutes, err = getEnvMinutes("COOLDOWN_WATCHLIST_MINUTES"); err != nil {
+		return nil, err
+	}
+	if cfg.FlipSymbolMinutes, err = getEnvMinutes("FLIP_SYMBOL_MINUTES"); err != nil {
+		return nil, err
+	}
+	if cfg.DecisionRulesMinConfidence, err = getEnvThresholds("DECISION_RULES_MIN_CONFIDENCE"); err != nil {
+		return nil, err
+	}
+	// Each topic is routed to one event type, so topics must not be shared
+	topics := make(map[string]string)
+	for _, topic := range []struct{ env, name string }{
+		{"KAFKA_DECISION_TOPIC", cfg.KafkaDecisionTopic},
+		{"KAFKA_RANKING_TOPIC", cfg.KafkaRankingTopic},
+		{"KAFKA_PRICE_TOPIC", cfg.KafkaQuoteTopic},
+		{"KAFKA_INDICATOR_TOPIC", cfg.KafkaIndicatorTopic},
+		{"KAFKA_POSITIONS_TOPIC", cfg.KafkaPositionTopic},
+	} {
+		if topic.name == "" {
+			continue
+		}
+		if other, ok := topics[topic.name]; ok {
+			return nil, fmt.Errorf("%s and %s both use topic %q", other, topic.env, topic.name)
+		}
+		topics[topic.name] = topic.env
+	}
+	if cfg.DecisionRulesMinAgreeing < 0 {
+		return nil, fmt.Errorf("DECISION_RULES_MIN_AGREEING must not be negative")
+	}
+	if cfg.FlipWindowMinutes < 0 {
+		return nil, fmt.Errorf("FLIP_WINDOW_MINUTES must not be negative")
+	}
+	if cfg.ConfidenceTrendPoints < 0 || cfg.ConfidenceTrendHours < 0 || cfg.ConfidenceRisingStreak < 0 {
+		return nil, fmt.Errorf("CONFIDENCE_TREND_POINTS, CONFIDENCE_TREND_HOURS and CONFIDENCE_RISING_STREAK must not be negative")
+	}
+	if cfg.ConfidenceTrendPoints > 0 && cfg.ConfidenceRisingStreak >= cfg.ConfidenceTrendPoints {
+		return nil, fmt.Errorf("CONFIDENCE_RISING_STREAK must be below CONFIDENCE_TREND_POINTS, got %d >= %d",
+			cfg.ConfidenceRisingStreak, cfg.ConfidenceTrendPoints)
+	}
+
+	if cfg.CooldownAdaptive && (cfg.CooldownAdaptiveWindow <= 0 || cfg.CooldownAdaptiveMax <= 0) {
+		return nil, fmt.Errorf("COOLDOWN_ADAPTIVE requires positive COOLDOWN_ADAPTIVE_WINDOW_MINUTES and COOLDOWN_ADAPTIVE_MAX_MINUTES")
+	}
+
+	// Keep cooldowns at least as long as the longest configured cooldown
+	longest := cfg.CooldownMinutes
+	if cfg.CooldownAdaptive {
+		// Decay needs the last alert for at least one window
+		for _, minutes := range []int{cfg.CooldownAdaptiveMax, cfg.CooldownAdaptiveWindow} {
+			if minutes > longest {
+				longest = minutes
+			}
+		}
+	}
+	for _, overrides := range []map[string]int{cfg.CooldownSignalMinutes, cfg.CooldownSymbolMinutes, cfg.CooldownWatchlistMinutes} {
+		for _, minutes := range overrides {
+			if minutes > longest {
+				longest = minutes
+			}
+		}
+	}
+	if minHours := (longest + 59) / 60; cfg.CooldownRetentionHours < minHours {
+		cfg.CooldownRetentionHours = minHours
+	}
+
+	// QUIET_HOURS_START/END predate schedules and map to a daily window;
+	// equal hours never matched before, so they leave quiet hours off
+	if cfg.QuietHours == "" && cfg.EnableQuietHours && cfg.QuietHoursStart != cfg.QuietHoursEnd {
+		cfg.QuietHours = fmt.Sprintf("daily %02d:00-%02d:00", cfg.QuietHoursStart, cfg.QuietHoursEnd)
+	}
+	loc, err := schedule.LoadLocation(cfg.QuietHoursTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("QUIET_HOURS_TZ: %w", err)
+	}
+	if _, err := schedule.Parse(cfg.QuietHours, loc); err != nil {
+		return nil, fmt.Errorf("QUIET_HOURS: %w", err)
+	}
+
+	if _, err := calendar.ParseFilter(cfg.AlertSessions); err != nil {
+		return nil, fmt.Errorf("ALERT_SESSIONS: %w", err)
+	}
+
+	if cfg.SMTPHost != "" && (cfg.EmailFrom == "" || len(cfg.EmailTo) == 0) {
+		return nil, fmt.Errorf("SMTP_HOST requires EMAIL_FROM and EMAIL_TO")
+	}
+
+	digestLoc, err := schedule.LoadLocation(cfg.DigestTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("DIGEST_TZ: %w", err)
+	}
+	if _, err := schedule.ParseRecurrences(cfg.ChannelDigests, digestLoc); err != nil {
+		return nil, fmt.Errorf("CHANNEL_DIGESTS: %w", err)
+	}
+
+	reportLoc, err := schedule.LoadLocation(cfg.ReportTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("REPORT_TZ: %w", err)
+	}
+	for key, spec := range map[string]string{"REPORT_EOD": cfg.ReportEOD, "REPORT_WEEKLY": cfg.ReportWeekly} {
+		if spec == "" {
+			continue
+		}
+		if _, err := schedule.ParseRecurrence(spec, reportLoc); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	// Keep at least the ranking history summarized in alerts
+	if cfg.RankingRetentionDays > 0 && cfg.RankingRetentionDays < cfg.RankingTimelineDays {
+		cfg.RankingRetentionDays = cfg.RankingTimelineDays
+	}
+
+	// A rules file alone implies the file source
+	if cfg.RulesSource == "" && cfg.RulesFile != "" {
+		cfg.RulesSource = "file"
+	}
+
+	// Likewise for a positions file
+	if cfg.PositionsSource == "" && cfg.PositionsFile != "" {
+		cfg.PositionsSource = "file"
 	}
 
 	// Validate required fields
@@ -66,6 +408,75 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("TELEGRAM_CHAT_ID is required")
 	}
 
+	// RULES_SOURCE=postgres predates pluggable storage and selects the postgres backend
+	if cfg.RulesSource == "postgres" {
+		cfg.RulesSource = "store"
+		if cfg.StorageBackend == "" {
+			cfg.StorageBackend = "postgres"
+		}
+	}
+
+	switch cfg.StorageBackend {
+	case "", "postgres", "sqlite":
+	default:
+		return nil, fmt.Errorf("STORAGE_BACKEND must be postgres, sqlite or empty, got %q", cfg.StorageBackend)
+	}
+
+	switch cfg.RulesSource {
+	case "":
+	case "store":
+		if cfg.StorageBackend == "" {
+			return nil, fmt.Errorf("RULES_SOURCE=store requires STORAGE_BACKEND")
+		}
+	case "file":
+		if cfg.RulesFile == "" {
+			return nil, fmt.Errorf("RULES_FILE is required when RULES_SOURCE=file")
+		}
+	default:
+		return nil, fmt.Errorf("RULES_SOURCE must be file or store, got %q", cfg.RulesSource)
+	}
+
+	switch cfg.PositionsSource {
+	case "", "topic":
+	case "file":
+		if cfg.PositionsFile == "" {
+			return nil, fmt.Errorf("POSITIONS_FILE is required when POSITIONS_SOURCE=file")
+		}
+	default:
+		return nil, fmt.Errorf("POSITIONS_SOURCE must be file or topic, got %q", cfg.PositionsSource)
+	}
+
+	if cfg.SizingAccountSize < 0 {
+		return nil, fmt.Errorf("SIZING_ACCOUNT_SIZE must not be negative")
+	}
+	if cfg.SizingAccountSize > 0 {
+		if cfg.SizingRiskPercent <= 0 || cfg.SizingRiskPercent > 100 {
+			return nil, fmt.Errorf("SIZING_RISK_PERCENT must be between 0 and 100, got %g", cfg.SizingRiskPercent)
+		}
+		if cfg.SizingStopMultiple <= 0 {
+			return nil, fmt.Errorf("SIZING_STOP_MULTIPLE must be positive, got %g", cfg.SizingStopMultiple)
+		}
+		if cfg.SizingMaxPositionPercent < 0 {
+			return nil, fmt.Errorf("SIZING_MAX_POSITION_PERCENT must not be negative")
+		}
+	}
+
+	if _, err := watchlist.Parse(cfg.Watchlists); err != nil {
+		return nil, fmt.Errorf("WATCHLISTS: %w", err)
+	}
+	if _, err := ParseChannelMap(cfg.WatchlistChannels); err != nil {
+		return nil, fmt.Errorf("WATCHLIST_CHANNELS: %w", err)
+	}
+	switch cfg.WatchlistUnlisted {
+	case "allow", "drop":
+	case "route":
+		if len(cfg.LowPriorityChannels) == 0 {
+			return nil, fmt.Errorf("LOW_PRIORITY_CHANNELS is required when WATCHLIST_UNLISTED=route")
+		}
+	default:
+		return nil, fmt.Errorf("WATCHLIST_UNLISTED must be allow, drop or route, got %q", cfg.WatchlistUnlisted)
+	}
+
 	return cfg, nil
 }
 
@@ -111,3 +522,81 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// ParseChannelMap parses "name=channel,channel; name=channel" into channel
+// lists keyed by lower-case name
+func ParseChannelMap(value string) (map[string][]string, error) {
+	out := make(map[string][]string)
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, channels, ok := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		list := splitList(strings.ToLower(channels))
+		if !ok || name == "" || len(list) == 0 {
+			return nil, fmt.Errorf("invalid entry %q, want name=channel,channel", strings.TrimSpace(entry))
+		}
+		out[name] = list
+	}
+	return out, nil
+}
+
+// getEnvMinutes parses a comma-separated list of NAME:minutes pairs.
+// Names are upper-cased.
+func getEnvMinutes(key string) (map[string]int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	out := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, minutes, ok := strings.Cut(pair, ":")
+		n, err := strconv.Atoi(strings.TrimSpace(minutes))
+		if !ok || err != nil || n < 0 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%s: invalid entry %q, want NAME:minutes", key, pair)
+		}
+		out[strings.ToUpper(strings.TrimSpace(name))] = n
+	}
+	return out, nil
+}
+
+// getEnvThresholds parses a comma-separated list of name:confidence pairs
+// with confidences between 0 and 1. Names are lower-cased.
+func getEnvThresholds(key string) (map[string]float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	out := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, confidence, ok := strings.Cut(pair, ":")
+		f, err := strconv.ParseFloat(strings.TrimSpace(confidence), 64)
+		if !ok || err != nil || f < 0 || f > 1 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%s: invalid entry %q, want name:confidence between 0 and 1", key, pair)
+		}
+		out[strings.ToLower(strings.TrimSpace(name))] = f
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import "testing"
+
+func TestLegacyQuietHours(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		want       string
+	}{
+		{"overnight window", "22", "7", "daily 22:00-07:00"},
+		{"daytime window", "12", "13", "daily 12:00-13:00"},
+		{"equal hours stay off", "22", "22", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TELEGRAM_BOT_TOKEN", "test")
+			t.Setenv("TELEGRAM_CHAT_ID", "1")
+			t.Setenv("ENABLE_QUIET_HOURS", "true")
+			t.Setenv("QUIET_HOURS", "")
+			t.Setenv("QUIET_HOURS_START", tt.start)
+			t.Setenv("QUIET_HOURS_END", tt.end)
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.QuietHours != tt.want {
+				t.Errorf("QuietHours = %q, want %q", cfg.QuietHours, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateTopics(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test")
+	t.Setenv("TELEGRAM_CHAT_ID", "1")
+
+	t.Setenv("KAFKA_INDICATOR_TOPIC", "trading.decisions")
+	if _, err := Load(); err == nil || err.Error() != `KAFKA_DECISION_TOPIC and KAFKA_INDICATOR_TOPIC both use topic "trading.decisions"` {
+		t.Errorf("Load() with a shared topic: error = %v", err)
+	}
+
+	// Unsubscribed topics are empty and never clash
+	t.Setenv("KAFKA_INDICATOR_TOPIC", "")
+	t.Setenv("KAFKA_POSITIONS_TOPIC", "")
+	if _, err := Load(); err != nil {
+		t.Errorf("Load() with two empty topics: %v", err)
+	}
+}
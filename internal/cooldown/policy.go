@@ -0,0 +1,179 @@
+package cooldown
+
+import (
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+// Reasons an alert was allowed
+const (
+	ReasonNone            = ""                 // no previous alert for the symbol and signal
+	ReasonExpired         = "expired"          // the cooldown elapsed
+	ReasonDirectionChange = "direction_change" // the symbol's last alert was the opposite BUY or SELL
+	ReasonConfidenceJump  = "confidence_jump"  // confidence rose enough since the last alert
+)
+
+// confidenceEpsilon absorbs float rounding, so 0.70 -> 0.85 counts as 15 points
+const confidenceEpsilon = 1e-9
+
+// Entry is the last alert sent for a symbol or a symbol and signal
+type Entry struct {
+	At         time.Time
+	Signal     string
+	Confidence float64
+	Level      int // escalation level the alert was sent at
+}
+
+// Decision is the outcome of a cooldown check
+type Decision struct {
+	Allowed   bool
+	Reason    string        // why an alert was allowed despite a previous one
+	Remaining time.Duration // time left when the alert is not allowed
+}
+
+// Policy decides whether a decision alert may be sent. Cooldowns are kept
+// per symbol and signal, so a SELL is never held back by an earlier BUY.
+type Policy struct {
+	Default time.Duration            // cooldown when no override applies
+	Signals map[string]time.Duration // per-signal durations, keyed by upper-case signal
+	Symbols map[string]time.Duration // per-symbol durations, keyed by upper-case symbol; wins over Lists and Signals
+	Lists   map[string]time.Duration // per-watchlist durations, keyed by lower-case name; wins over Signals
+
+	// ListsOf returns the watchlists a symbol is on; the longest of their
+	// durations applies. Nil disables watchlist durations.
+	ListsOf func(symbol string) []string
+
+	// BypassOnDirectionChange lets a BUY or SELL through when the symbol's
+	// most recent alert was the opposite one, e.g. BUY -> SELL -> BUY. WATCH
+	// is not a direction, so BUY -> WATCH -> BUY stays in the cooldown.
+	BypassOnDirectionChange bool
+
+	// ConfidenceJump re-alerts inside the cooldown when confidence rose by
+	// at least this much (0.15 = 15 points). Zero disables it.
+	ConfidenceJump float64
+
+	// Adaptive doubles the cooldown for every repeated alert sent within
+	// Window of the previous one, up to Max, which must be set. Each full
+	// Window without an alert steps the cooldown back down one level.
+	Adaptive bool
+	Window   time.Duration
+	Max      time.Duration
+}
+
+// Key returns the cooldown key for a symbol and signal
+func Key(symbol, signal string) string {
+	return symbol + ":" + signal
+}
+
+// Duration returns the cooldown for a symbol and signal
+func (p Policy) Duration(symbol, signal string) time.Duration {
+	if d, ok := p.Symbols[strings.ToUpper(symbol)]; ok {
+		return d
+	}
+	if d, ok := p.listDuration(symbol); ok {
+		return d
+	}
+	if d, ok := p.Signals[strings.ToUpper(signal)]; ok {
+		return d
+	}
+	return p.Default
+}
+
+// listDuration returns the longest duration of the symbol's watchlists
+func (p Policy) listDuration(symbol string) (time.Duration, bool) {
+	if p.ListsOf == nil || len(p.Lists) == 0 {
+		return 0, false
+	}
+	var longest time.Duration
+	found := false
+	for _, name := range p.ListsOf(symbol) {
+		if d, ok := p.Lists[name]; ok && (!found || d > longest) {
+			longest, found = d, true
+		}
+	}
+	return longest, found
+}
+
+// Effective returns the cooldown for a symbol and signal at an escalation level
+func (p Policy) Effective(symbol, signal string, level int) time.Duration {
+	d := p.Duration(symbol, signal)
+	if !p.Adaptive {
+		return d
+	}
+	for i := 0; i < level; i++ {
+		if p.Max > 0 && d >= p.Max {
+			break
+		}
+		d *= 2
+	}
+	if p.Max > 0 && d > p.Max {
+		d = p.Max
+	}
+	return d
+}
+
+// NextLevel returns the escalation level for an alert for symbol and signal
+// sent at now, given the previous alert for them (nil if none). The level
+// stops rising once the cooldown has reached Max.
+func (p Policy) NextLevel(now time.Time, symbol, signal string, prev *Entry) int {
+	if !p.Adaptive || prev == nil || p.Window <= 0 || p.Max <= 0 || p.Duration(symbol, signal) <= 0 {
+		return 0
+	}
+
+	if now.Sub(prev.At) < p.Window {
+		if p.Effective(symbol, signal, prev.Level) >= p.Max {
+			return prev.Level
+		}
+		return prev.Level + 1
+	}
+	return p.Level(now, prev)
+}
+
+// Level returns the escalation level in force at now after the previous
+// alert: its level, stepped down one for each full Window since it was sent
+func (p Policy) Level(now time.Time, prev *Entry) int {
+	if prev == nil {
+		return 0
+	}
+	if !p.Adaptive || p.Window <= 0 {
+		return prev.Level
+	}
+	level := prev.Level - int(now.Sub(prev.At)/p.Window)
+	if level < 0 {
+		level = 0
+	}
+	return level
+}
+
+// Check decides whether an alert for symbol and signal may be sent at now.
+// same is the last alert for the symbol and signal and latest the last alert
+// for the symbol with any signal; either may be nil.
+func (p Policy) Check(now time.Time, symbol, signal string, confidence float64, same, latest *Entry) Decision {
+	if same == nil {
+		return Decision{Allowed: true, Reason: ReasonNone}
+	}
+
+	remaining := p.Effective(symbol, signal, p.Level(now, same)) - now.Sub(same.At)
+	if remaining <= 0 {
+		return Decision{Allowed: true, Reason: ReasonExpired}
+	}
+
+	if p.BypassOnDirectionChange && latest != nil && latest.At.After(same.At) &&
+		opposite(latest.Signal, signal) {
+		return Decision{Allowed: true, Reason: ReasonDirectionChange}
+	}
+
+	if p.ConfidenceJump > 0 && confidence-same.Confidence >= p.ConfidenceJump-confidenceEpsilon {
+		return Decision{Allowed: true, Reason: ReasonConfidenceJump}
+	}
+
+	return Decision{Remaining: remaining}
+}
+
+// opposite reports whether two signals are BUY and SELL in either order
+func opposite(a, b string) bool {
+	a, b = strings.ToUpper(a), strings.ToUpper(b)
+	return (a == models.SignalBuy && b == models.SignalSell) || (a == models.SignalSell && b == models.SignalBuy)
+}
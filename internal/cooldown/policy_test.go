@@ -0,0 +1,173 @@
+package cooldown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	ago := func(minutes int) time.Time { return now.Add(-time.Duration(minutes) * time.Minute) }
+
+	policy := Policy{
+		Default:                 30 * time.Minute,
+		BypassOnDirectionChange: true,
+		ConfidenceJump:          0.15,
+	}
+
+	tests := []struct {
+		name       string
+		signal     string
+		confidence float64
+		same       *Entry
+		latest     *Entry
+		want       Decision
+	}{
+		{
+			name:   "first alert",
+			signal: "BUY",
+			want:   Decision{Allowed: true, Reason: ReasonNone},
+		},
+		{
+			name:   "expired",
+			signal: "BUY",
+			same:   &Entry{At: ago(31), Signal: "BUY"},
+			want:   Decision{Allowed: true, Reason: ReasonExpired},
+		},
+		{
+			name:       "in cooldown",
+			signal:     "BUY",
+			confidence: 0.7,
+			same:       &Entry{At: ago(10), Signal: "BUY", Confidence: 0.7},
+			latest:     &Entry{At: ago(10), Signal: "BUY", Confidence: 0.7},
+			want:       Decision{Remaining: 20 * time.Minute},
+		},
+		{
+			name:       "BUY -> SELL -> BUY bypasses",
+			signal:     "BUY",
+			confidence: 0.7,
+			same:       &Entry{At: ago(10), Signal: "BUY", Confidence: 0.7},
+			latest:     &Entry{At: ago(5), Signal: "SELL", Confidence: 0.7},
+			want:       Decision{Allowed: true, Reason: ReasonDirectionChange},
+		},
+		{
+			name:       "BUY -> WATCH -> BUY stays in cooldown",
+			signal:     "BUY",
+			confidence: 0.7,
+			same:       &Entry{At: ago(10), Signal: "BUY", Confidence: 0.7},
+			latest:     &Entry{At: ago(5), Signal: "WATCH", Confidence: 0.7},
+			want:       Decision{Remaining: 20 * time.Minute},
+		},
+		{
+			name:       "WATCH -> BUY -> WATCH stays in cooldown",
+			signal:     "WATCH",
+			confidence: 0.7,
+			same:       &Entry{At: ago(10), Signal: "WATCH", Confidence: 0.7},
+			latest:     &Entry{At: ago(5), Signal: "BUY", Confidence: 0.7},
+			want:       Decision{Remaining: 20 * time.Minute},
+		},
+		{
+			name:       "confidence jump",
+			signal:     "SELL",
+			confidence: 0.85,
+			same:       &Entry{At: ago(10), Signal: "SELL", Confidence: 0.70},
+			latest:     &Entry{At: ago(10), Signal: "SELL", Confidence: 0.70},
+			want:       Decision{Allowed: true, Reason: ReasonConfidenceJump},
+		},
+		{
+			name:       "confidence rise below the jump",
+			signal:     "SELL",
+			confidence: 0.80,
+			same:       &Entry{At: ago(10), Signal: "SELL", Confidence: 0.70},
+			latest:     &Entry{At: ago(10), Signal: "SELL", Confidence: 0.70},
+			want:       Decision{Remaining: 20 * time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Check(now, "AAPL", tt.signal, tt.confidence, tt.same, tt.latest)
+			if got != tt.want {
+				t.Errorf("Check() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyDuration(t *testing.T) {
+	policy := Policy{
+		Default: 30 * time.Minute,
+		Signals: map[string]time.Duration{"SELL": 10 * time.Minute},
+		Symbols: map[string]time.Duration{"TSLA": 90 * time.Minute},
+		Lists:   map[string]time.Duration{"metals": 120 * time.Minute, "core": 60 * time.Minute},
+		ListsOf: func(symbol string) []string {
+			if symbol == "GLD" || symbol == "TSLA" {
+				return []string{"core", "metals"}
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		symbol, signal string
+		want           time.Duration
+	}{
+		{"AAPL", "BUY", 30 * time.Minute},
+		{"AAPL", "sell", 10 * time.Minute},
+		{"GLD", "SELL", 120 * time.Minute},
+		{"tsla", "SELL", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := policy.Duration(tt.symbol, tt.signal); got != tt.want {
+			t.Errorf("Duration(%s, %s) = %s, want %s", tt.symbol, tt.signal, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyAdaptive(t *testing.T) {
+	policy := Policy{Default: 30 * time.Minute, Adaptive: true, Window: 2 * time.Hour, Max: 4 * time.Hour}
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		prev *Entry
+		want int
+	}{
+		{"no previous alert", nil, 0},
+		{"repeat within the window", &Entry{At: now.Add(-time.Hour), Level: 1}, 2},
+		{"stops rising at the max", &Entry{At: now.Add(-time.Hour), Level: 3}, 3},
+		{"decays one level per window", &Entry{At: now.Add(-5 * time.Hour), Level: 3}, 1},
+		{"decays to zero", &Entry{At: now.Add(-24 * time.Hour), Level: 2}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.NextLevel(now, "AAPL", "BUY", tt.prev); got != tt.want {
+				t.Errorf("NextLevel() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	for level, want := range []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour, 4 * time.Hour, 4 * time.Hour} {
+		if got := policy.Effective("AAPL", "BUY", level); got != want {
+			t.Errorf("Effective(level %d) = %s, want %s", level, got, want)
+		}
+	}
+}
+
+func TestPolicyLevelDecay(t *testing.T) {
+	policy := Policy{Default: 30 * time.Minute, Adaptive: true, Window: 30 * time.Minute, Max: 8 * time.Hour}
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	prev := &Entry{At: now.Add(-65 * time.Minute), Signal: "BUY", Level: 3}
+
+	// Two full windows have passed, so level 3 (4h) has decayed to level 1 (1h)
+	if got := policy.Level(now, prev); got != 1 {
+		t.Fatalf("Level() = %d, want 1", got)
+	}
+	// Check enforces the decayed level: 1h cooldown, 65m elapsed
+	if got := policy.Check(now, "AAPL", "BUY", 0, prev, prev); !got.Allowed {
+		t.Errorf("Check() = %+v, want allowed after decay", got)
+	}
+	if got := (Policy{Default: time.Hour}).Level(now, prev); got != 3 {
+		t.Errorf("Level() without adaptive = %d, want the stored level 3", got)
+	}
+}
@@ -0,0 +1,145 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html"
+	"mime"
+	"net"
+	"net/smtp"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sendTimeout bounds an SMTP exchange when the context has no deadline
+const sendTimeout = 30 * time.Second
+
+// Config holds SMTP settings
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Client sends alerts by email over SMTP
+type Client struct {
+	config Config
+}
+
+// NewClient creates a new email client
+func NewClient(cfg Config) *Client {
+	return &Client{config: cfg}
+}
+
+// Name identifies the client as a notification channel
+func (c *Client) Name() string {
+	return "email"
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// SendMessage sends an alert rendered in Telegram HTML as an HTML email.
+// The subject is the message's first line without markup.
+func (c *Client) SendMessage(ctx context.Context, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	subject, _, _ := strings.Cut(message, "\n")
+	subject = html.UnescapeString(strings.TrimSpace(tagPattern.ReplaceAllString(subject, "")))
+	if subject == "" {
+		subject = "Trading alert"
+	}
+
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", c.config.From))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(c.config.To, ", ")))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject)))
+	msg.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	msg.WriteString(`<div style="font-family: sans-serif; white-space: pre-wrap">`)
+	msg.WriteString(strings.ReplaceAll(message, "\n", "\r\n"))
+	msg.WriteString("</div>\r\n")
+
+	var auth smtp.Auth
+	if c.config.Username != "" {
+		auth = smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)
+	}
+
+	if err := c.send(ctx, auth, []byte(msg.String())); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// send delivers msg like smtp.SendMail, but bounded by ctx: the whole
+// exchange must finish by the context deadline, or within sendTimeout
+// without one, and cancelling ctx aborts it
+func (c *Client) send(ctx context.Context, auth smtp.Auth, msg []byte) error {
+	addr := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+	dialer := net.Dialer{Timeout: sendTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(sendTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.config.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("server does not support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.config.From); err != nil {
+		return err
+	}
+	for _, to := range c.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
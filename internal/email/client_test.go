@@ -0,0 +1,120 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTP accepts one connection and answers like a minimal SMTP server,
+// or stays silent when hang is set. It returns the message data received.
+func fakeSMTP(t *testing.T, hang bool) (host string, port int, data <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if hang {
+			time.Sleep(5 * time.Second)
+			return
+		}
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var body strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					body.WriteString(line)
+				}
+				received <- body.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func TestSendMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		hang    bool
+		timeout time.Duration
+		wantErr bool
+	}{
+		{name: "delivered", timeout: 5 * time.Second},
+		{name: "unresponsive server stops at the context deadline", hang: true, timeout: 200 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port, data := fakeSMTP(t, tt.hang)
+			c := NewClient(Config{Host: host, Port: port, From: "alerts@example.com", To: []string{"me@example.com"}})
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			start := time.Now()
+			err := c.SendMessage(ctx, "🚨 <b>AAPL &amp; MSFT</b>\nBUY")
+			if elapsed := time.Since(start); elapsed > tt.timeout+time.Second {
+				t.Errorf("SendMessage took %s, want it bounded by the %s context", elapsed, tt.timeout)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendMessage error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			body := <-data
+			for _, want := range []string{"To: me@example.com", "Subject: =?UTF-8?q?", "Content-Type: text/html", "<b>AAPL &amp; MSFT</b>"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("message missing %q:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestSendMessageCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := NewClient(Config{Host: "127.0.0.1", Port: 1, From: "a@example.com", To: []string{"b@example.com"}})
+	if err := c.SendMessage(ctx, "test"); err == nil {
+		t.Error("SendMessage with a cancelled context succeeded")
+	}
+}
@@ -0,0 +1,109 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Type is the static type of an expression node
+type Type int
+
+const (
+	TypeInvalid Type = iota
+	TypeNumber
+	TypeBool
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeNumber:
+		return "number"
+	case TypeBool:
+		return "bool"
+	default:
+		return "invalid"
+	}
+}
+
+// Error is a syntax or type error with the column it occurred at
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos, e.Msg)
+}
+
+// node is an element of the expression syntax tree
+type node interface {
+	pos() int
+	String() string
+}
+
+type numberLit struct {
+	at    int
+	value float64
+}
+
+type boolLit struct {
+	at    int
+	value bool
+}
+
+type ident struct {
+	at   int
+	name string
+}
+
+type unary struct {
+	at      int
+	op      tokenKind
+	operand node
+}
+
+type binary struct {
+	at          int
+	op          tokenKind
+	left, right node
+}
+
+type call struct {
+	at   int
+	name string
+	args []node
+	fn   *function
+	slot int // state slot for stateful functions
+}
+
+func (n *numberLit) pos() int { return n.at }
+func (n *boolLit) pos() int   { return n.at }
+func (n *ident) pos() int     { return n.at }
+func (n *unary) pos() int     { return n.at }
+func (n *binary) pos() int    { return n.at }
+func (n *call) pos() int      { return n.at }
+
+func (n *numberLit) String() string { return strconv.FormatFloat(n.value, 'f', -1, 64) }
+func (n *boolLit) String() string   { return strconv.FormatBool(n.value) }
+func (n *ident) String() string     { return n.name }
+
+func (n *unary) String() string {
+	return fmt.Sprintf("%s%s", opText(n.op), n.operand)
+}
+
+func (n *binary) String() string {
+	return fmt.Sprintf("(%s %s %s)", n.left, opText(n.op), n.right)
+}
+
+func (n *call) String() string {
+	args := make([]string, len(n.args))
+	for i, a := range n.args {
+		args[i] = a.String()
+	}
+	return fmt.Sprintf("%s(%s)", n.name, strings.Join(args, ", "))
+}
+
+func opText(op tokenKind) string {
+	return strings.Trim(op.String(), "'")
+}
@@ -0,0 +1,187 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+)
+
+// Schema describes the numeric identifiers an expression may reference
+type Schema interface {
+	Known(name string) bool
+}
+
+// function is a built-in callable from expressions
+type function struct {
+	params   []Type
+	optional int  // number of trailing params that may be omitted
+	variadic bool // last param may repeat
+	result   Type
+	impl     func(args []value) (value, error)
+
+	// step implements stateful functions, which remember what they saw on
+	// the previous evaluation; impl is unused for them
+	step func(s *slot, args []value) bool
+}
+
+var builtins = map[string]*function{
+	"abs": {
+		params: []Type{TypeNumber},
+		result: TypeNumber,
+		impl: func(args []value) (value, error) {
+			return numberValue(math.Abs(args[0].num)), nil
+		},
+	},
+	"min": {
+		params:   []Type{TypeNumber, TypeNumber},
+		variadic: true,
+		result:   TypeNumber,
+		impl: func(args []value) (value, error) {
+			m := args[0].num
+			for _, a := range args[1:] {
+				m = math.Min(m, a.num)
+			}
+			return numberValue(m), nil
+		},
+	},
+	"max": {
+		params:   []Type{TypeNumber, TypeNumber},
+		variadic: true,
+		result:   TypeNumber,
+		impl: func(args []value) (value, error) {
+			m := args[0].num
+			for _, a := range args[1:] {
+				m = math.Max(m, a.num)
+			}
+			return numberValue(m), nil
+		},
+	},
+	"pct_change": {
+		params: []Type{TypeNumber, TypeNumber},
+		result: TypeNumber,
+		impl: func(args []value) (value, error) {
+			from, to := args[0].num, args[1].num
+			if from == 0 {
+				return value{}, fmt.Errorf("pct_change: division by zero")
+			}
+			return numberValue((to - from) / from * 100), nil
+		},
+	},
+}
+
+// checker resolves identifiers and function calls and infers node types
+type checker struct {
+	schema Schema
+	idents []string
+	seen   map[string]bool
+	slots  int // stateful call sites allocated so far
+}
+
+func (c *checker) check(n node) (Type, error) {
+	switch n := n.(type) {
+	case *numberLit:
+		return TypeNumber, nil
+
+	case *boolLit:
+		return TypeBool, nil
+
+	case *ident:
+		if c.schema != nil && !c.schema.Known(n.name) {
+			return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("unknown identifier %q", n.name)}
+		}
+		if !c.seen[n.name] {
+			c.seen[n.name] = true
+			c.idents = append(c.idents, n.name)
+		}
+		return TypeNumber, nil
+
+	case *unary:
+		t, err := c.check(n.operand)
+		if err != nil {
+			return TypeInvalid, err
+		}
+		want := TypeNumber
+		if n.op == tokNot {
+			want = TypeBool
+		}
+		if t != want {
+			return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("operator %s requires a %s operand, got %s", n.op, want, t)}
+		}
+		return want, nil
+
+	case *binary:
+		lt, err := c.check(n.left)
+		if err != nil {
+			return TypeInvalid, err
+		}
+		rt, err := c.check(n.right)
+		if err != nil {
+			return TypeInvalid, err
+		}
+		switch n.op {
+		case tokPlus, tokMinus, tokStar, tokSlash, tokPercent:
+			if lt != TypeNumber || rt != TypeNumber {
+				return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("operator %s requires numbers, got %s and %s", n.op, lt, rt)}
+			}
+			return TypeNumber, nil
+		case tokLT, tokLE, tokGT, tokGE:
+			if lt != TypeNumber || rt != TypeNumber {
+				return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("operator %s requires numbers, got %s and %s", n.op, lt, rt)}
+			}
+			return TypeBool, nil
+		case tokEQ, tokNE:
+			if lt != rt {
+				return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("operator %s cannot compare %s with %s", n.op, lt, rt)}
+			}
+			return TypeBool, nil
+		case tokAnd, tokOr:
+			if lt != TypeBool || rt != TypeBool {
+				return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("operator %s requires bools, got %s and %s", n.op, lt, rt)}
+			}
+			return TypeBool, nil
+		}
+		return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("unsupported operator %s", n.op)}
+
+	case *call:
+		fn, ok := builtins[n.name]
+		if !ok {
+			return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("unknown function %q", n.name)}
+		}
+		if len(n.args) < len(fn.params)-fn.optional || (!fn.variadic && len(n.args) > len(fn.params)) {
+			return TypeInvalid, &Error{Pos: n.at, Msg: fmt.Sprintf("%s expects %s, got %d", n.name, arity(fn), len(n.args))}
+		}
+		for i, arg := range n.args {
+			t, err := c.check(arg)
+			if err != nil {
+				return TypeInvalid, err
+			}
+			want := fn.params[len(fn.params)-1]
+			if i < len(fn.params) {
+				want = fn.params[i]
+			}
+			if t != want {
+				return TypeInvalid, &Error{Pos: arg.pos(), Msg: fmt.Sprintf("argument %d of %s must be a %s, got %s", i+1, n.name, want, t)}
+			}
+		}
+		n.fn = fn
+		if fn.step != nil {
+			n.slot = c.slots
+			c.slots++
+		}
+		return fn.result, nil
+	}
+
+	return TypeInvalid, &Error{Pos: n.pos(), Msg: "unsupported expression"}
+}
+
+func arity(fn *function) string {
+	if fn.variadic {
+		return fmt.Sprintf("at least %d arguments", len(fn.params))
+	}
+	if fn.optional > 0 {
+		return fmt.Sprintf("%d to %d arguments", len(fn.params)-fn.optional, len(fn.params))
+	}
+	if len(fn.params) == 1 {
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", len(fn.params))
+}
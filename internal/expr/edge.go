@@ -0,0 +1,83 @@
+package expr
+
+import "math"
+
+// State is the memory of a program's stateful functions for one evaluation
+// context, e.g. one rule on one symbol. It is not safe for concurrent use.
+type State struct {
+	slots []slot
+}
+
+// NewState creates empty state for a program
+func NewState(p *Program) *State {
+	return &State{slots: make([]slot, p.slots)}
+}
+
+// slot remembers whether a stateful call site is armed to fire
+type slot struct {
+	seen  bool
+	armed bool
+}
+
+// Edge-triggered functions fire once when their condition becomes true and
+// re-arm only after it has been false again. The optional trailing margin
+// is a hysteresis band: the value must move at least that far back across
+// the boundary before the function can fire again.
+//
+//	crosses_above(a, b [, margin])     a moves from <= b to > b
+//	crosses_below(a, b [, margin])     a moves from >= b to < b
+//	enters_range(x, lo, hi [, margin]) x moves from outside [lo, hi] to inside
+//	exits_range(x, lo, hi [, margin])  x moves from inside [lo, hi] to outside
+func init() {
+	edge := func(params int, step func(s *slot, args []value) bool) *function {
+		types := make([]Type, params+1)
+		for i := range types {
+			types[i] = TypeNumber
+		}
+		return &function{params: types, optional: 1, result: TypeBool, step: step}
+	}
+
+	builtins["crosses_above"] = edge(2, func(s *slot, args []value) bool {
+		diff := args[0].num - args[1].num
+		return s.advance(diff > 0, diff <= -margin(args, 2))
+	})
+	builtins["crosses_below"] = edge(2, func(s *slot, args []value) bool {
+		diff := args[0].num - args[1].num
+		return s.advance(diff < 0, diff >= margin(args, 2))
+	})
+	builtins["enters_range"] = edge(3, func(s *slot, args []value) bool {
+		x, lo, hi, m := args[0].num, args[1].num, args[2].num, margin(args, 3)
+		return s.advance(x >= lo && x <= hi, x < lo-m || x > hi+m)
+	})
+	builtins["exits_range"] = edge(3, func(s *slot, args []value) bool {
+		x, lo, hi, m := args[0].num, args[1].num, args[2].num, margin(args, 3)
+		return s.advance(x < lo-m || x > hi+m, x >= lo && x <= hi)
+	})
+}
+
+// advance records one observation and reports whether the call site fires.
+// fired is true when the condition holds; rearm is true when the value is
+// far enough on the other side of the boundary to allow firing again. The
+// first observation only establishes the starting side and never fires.
+func (s *slot) advance(fired, rearm bool) bool {
+	if !s.seen {
+		s.seen = true
+		s.armed = rearm
+		return false
+	}
+	if s.armed && fired {
+		s.armed = false
+		return true
+	}
+	if !s.armed && rearm {
+		s.armed = true
+	}
+	return false
+}
+
+func margin(args []value, i int) float64 {
+	if i < len(args) {
+		return math.Abs(args[i].num)
+	}
+	return 0
+}
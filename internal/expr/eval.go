@@ -0,0 +1,254 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnavailable is returned when an expression references a value that is
+// not currently known, e.g. an indicator that has not been published yet
+var ErrUnavailable = errors.New("value unavailable")
+
+// Vars supplies identifier values during evaluation
+type Vars interface {
+	Number(name string) (float64, bool)
+}
+
+// VarsFunc adapts a function to the Vars interface
+type VarsFunc func(name string) (float64, bool)
+
+// Number implements Vars
+func (f VarsFunc) Number(name string) (float64, bool) {
+	return f(name)
+}
+
+// value is the result of evaluating a node. For bools, margin is the signed
+// distance from the decision boundary: positive when true, zero or negative
+// when false, with its magnitude saying how far the inputs must move to flip
+// the result.
+type value struct {
+	num    float64
+	b      bool
+	margin float64
+}
+
+func numberValue(n float64) value { return value{num: n} }
+
+func boolValue(b bool) value {
+	if b {
+		return value{b: true, margin: math.Inf(1)}
+	}
+	return value{margin: math.Inf(-1)}
+}
+
+// Result is the outcome of evaluating a condition
+type Result struct {
+	Value  bool
+	Margin float64 // signed distance from flipping; see FalseBy
+}
+
+// FalseBy returns how far the condition is from becoming true, or zero if it holds
+func (r Result) FalseBy() float64 {
+	if r.Value {
+		return 0
+	}
+	return -r.Margin
+}
+
+// Program is a parsed and type-checked boolean expression
+type Program struct {
+	src    string
+	root   node
+	idents []string
+	slots  int
+}
+
+// Compile parses and type-checks a condition. Every identifier must be
+// known to the schema and the expression must evaluate to a bool, so all
+// errors surface here rather than at evaluation time.
+func Compile(src string, schema Schema) (*Program, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{schema: schema, seen: make(map[string]bool)}
+	t, err := c.check(root)
+	if err != nil {
+		return nil, err
+	}
+	if t != TypeBool {
+		return nil, &Error{Pos: 1, Msg: fmt.Sprintf("condition must be a bool expression, got %s", t)}
+	}
+
+	return &Program{src: src, root: root, idents: c.idents, slots: c.slots}, nil
+}
+
+// Source returns the original expression text
+func (p *Program) Source() string {
+	return p.src
+}
+
+// Identifiers returns the identifiers referenced by the expression in order
+// of first appearance
+func (p *Program) Identifiers() []string {
+	return append([]string(nil), p.idents...)
+}
+
+// Stateful reports whether the expression uses edge-triggered functions
+// and therefore needs a State to evaluate
+func (p *Program) Stateful() bool {
+	return p.slots > 0
+}
+
+// String returns the fully parenthesised form of the expression
+func (p *Program) String() string {
+	return p.root.String()
+}
+
+// Eval evaluates the expression. state carries memory between evaluations
+// for edge-triggered functions and may be nil only for programs that are
+// not Stateful. Errors wrapping ErrUnavailable mean the inputs are
+// incomplete rather than that the expression is invalid.
+func (p *Program) Eval(vars Vars, state *State) (Result, error) {
+	if p.Stateful() && (state == nil || len(state.slots) != p.slots) {
+		return Result{}, fmt.Errorf("expression %q requires state from NewState", p.src)
+	}
+	v, err := eval(p.root, vars, state)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Value: v.b, Margin: v.margin}, nil
+}
+
+func eval(n node, vars Vars, state *State) (value, error) {
+	switch n := n.(type) {
+	case *numberLit:
+		return numberValue(n.value), nil
+
+	case *boolLit:
+		return boolValue(n.value), nil
+
+	case *ident:
+		v, ok := vars.Number(n.name)
+		if !ok || math.IsNaN(v) {
+			return value{}, fmt.Errorf("%w: %s", ErrUnavailable, n.name)
+		}
+		return numberValue(v), nil
+
+	case *unary:
+		v, err := eval(n.operand, vars, state)
+		if err != nil {
+			return value{}, err
+		}
+		if n.op == tokNot {
+			return value{b: !v.b, margin: -v.margin}, nil
+		}
+		return numberValue(-v.num), nil
+
+	case *binary:
+		if n.op == tokAnd || n.op == tokOr {
+			return evalLogical(n, vars, state)
+		}
+
+		l, err := eval(n.left, vars, state)
+		if err != nil {
+			return value{}, err
+		}
+		r, err := eval(n.right, vars, state)
+		if err != nil {
+			return value{}, err
+		}
+		return evalBinary(n.op, l, r)
+
+	case *call:
+		args := make([]value, len(n.args))
+		for i, a := range n.args {
+			v, err := eval(a, vars, state)
+			if err != nil {
+				return value{}, err
+			}
+			args[i] = v
+		}
+		if n.fn.step != nil {
+			return boolValue(n.fn.step(&state.slots[n.slot], args)), nil
+		}
+		return n.fn.impl(args)
+	}
+
+	return value{}, fmt.Errorf("unsupported expression at col %d", n.pos())
+}
+
+// evalLogical evaluates both sides of && and || so edge-triggered functions
+// observe every update. A side whose inputs are unavailable is ignored when
+// the other side alone decides the result.
+func evalLogical(n *binary, vars Vars, state *State) (value, error) {
+	l, lerr := eval(n.left, vars, state)
+	r, rerr := eval(n.right, vars, state)
+
+	if n.op == tokAnd {
+		switch {
+		case lerr == nil && rerr == nil:
+			return value{b: l.b && r.b, margin: math.Min(l.margin, r.margin)}, nil
+		case lerr == nil && !l.b:
+			return l, nil
+		case rerr == nil && !r.b:
+			return r, nil
+		}
+	} else {
+		switch {
+		case lerr == nil && rerr == nil:
+			return value{b: l.b || r.b, margin: math.Max(l.margin, r.margin)}, nil
+		case lerr == nil && l.b:
+			return l, nil
+		case rerr == nil && r.b:
+			return r, nil
+		}
+	}
+
+	if lerr != nil {
+		return value{}, lerr
+	}
+	return value{}, rerr
+}
+
+func evalBinary(op tokenKind, l, r value) (value, error) {
+	switch op {
+	case tokPlus:
+		return numberValue(l.num + r.num), nil
+	case tokMinus:
+		return numberValue(l.num - r.num), nil
+	case tokStar:
+		return numberValue(l.num * r.num), nil
+	case tokSlash:
+		if r.num == 0 {
+			return value{}, fmt.Errorf("division by zero")
+		}
+		return numberValue(l.num / r.num), nil
+	case tokPercent:
+		if r.num == 0 {
+			return value{}, fmt.Errorf("division by zero")
+		}
+		return numberValue(math.Mod(l.num, r.num)), nil
+	case tokLT:
+		return value{b: l.num < r.num, margin: r.num - l.num}, nil
+	case tokLE:
+		return value{b: l.num <= r.num, margin: r.num - l.num}, nil
+	case tokGT:
+		return value{b: l.num > r.num, margin: l.num - r.num}, nil
+	case tokGE:
+		return value{b: l.num >= r.num, margin: l.num - r.num}, nil
+	case tokEQ:
+		if l.b != r.b {
+			return boolValue(false), nil
+		}
+		return value{b: l.num == r.num, margin: -math.Abs(l.num - r.num)}, nil
+	case tokNE:
+		if l.b != r.b {
+			return boolValue(true), nil
+		}
+		return value{b: l.num != r.num, margin: math.Abs(l.num - r.num)}, nil
+	}
+	return value{}, fmt.Errorf("unsupported operator %s", op)
+}
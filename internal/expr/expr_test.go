@@ -0,0 +1,274 @@
+package expr
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// schema accepts the identifiers listed in it
+type schema []string
+
+func (s schema) Known(name string) bool {
+	for _, n := range s {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+var testSchema = schema{"price", "rsi_14", "sma_20", "volume"}
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []tokenKind
+	}{
+		{"price > 1.5", []tokenKind{tokIdent, tokGT, tokNumber, tokEOF}},
+		{".5<=x", []tokenKind{tokNumber, tokLE, tokIdent, tokEOF}},
+		{"a>=b==c!=d", []tokenKind{tokIdent, tokGE, tokIdent, tokEQ, tokIdent, tokNE, tokIdent, tokEOF}},
+		{"!(a && b) || c", []tokenKind{tokNot, tokLParen, tokIdent, tokAnd, tokIdent, tokRParen, tokOr, tokIdent, tokEOF}},
+		{"max(a, -b) % 2 * 3 / 4 + 5", []tokenKind{tokIdent, tokLParen, tokIdent, tokComma, tokMinus, tokIdent, tokRParen, tokPercent, tokNumber, tokStar, tokNumber, tokSlash, tokNumber, tokPlus, tokNumber, tokEOF}},
+		{"", []tokenKind{tokEOF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			tokens, err := lex(tt.src)
+			if err != nil {
+				t.Fatalf("lex() error = %v", err)
+			}
+			if len(tokens) != len(tt.want) {
+				t.Fatalf("lex() = %d tokens, want %d", len(tokens), len(tt.want))
+			}
+			for i, tok := range tokens {
+				if tok.kind != tt.want[i] {
+					t.Errorf("token %d = %s, want %s", i, tok.kind, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"price = 1", `col 7: unexpected character '='`},
+		{"a & b", `col 3: unexpected character '&'`},
+		{"a | b", `col 3: unexpected character '|'`},
+		{"1.2.3 > a", `col 1: invalid number "1.2.3"`},
+		{"a > $", `col 5: unexpected character '$'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := lex(tt.src)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("lex() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"a + b * c", "(a + (b * c))"},
+		{"(a + b) * c", "((a + b) * c)"},
+		{"a - b - c", "((a - b) - c)"},
+		{"-a * b", "(-a * b)"},
+		{"a < b && c > d || e == f", "(((a < b) && (c > d)) || (e == f))"},
+		{"!a > b && true", "(!(a > b) && true)"},
+		{"max(a, b + 1, 2) >= 3", "(max(a, (b + 1), 2) >= 3)"},
+		{"f()", "f()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			n, err := parse(tt.src)
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+			if got := n.String(); got != tt.want {
+				t.Errorf("parse() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"  ", "col 1: empty expression"},
+		{"price >", "col 8: unexpected end of expression"},
+		{"price > 1)", "col 10: unexpected ')'"},
+		{"(price > 1", "col 11: expected ')'"},
+		{"price < 1 < 2", "col 11: comparisons cannot be chained; use &&"},
+		{"close > 1", `col 1: unknown identifier "close"`},
+		{"price + 1", "col 1: condition must be a bool expression, got number"},
+		{"price && true", "col 7: operator '&&' requires bools, got number and bool"},
+		{"(price > 1) + 1", "col 13: operator '+' requires numbers, got bool and number"},
+		{"price == true", "col 7: operator '==' cannot compare number with bool"},
+		{"!price", "col 1: operator '!' requires a bool operand, got number"},
+		{"median(price) > 1", `col 1: unknown function "median"`},
+		{"abs(price, 1) > 1", "col 1: abs expects 1 argument, got 2"},
+		{"max(price) > 1", "col 1: max expects at least 2 arguments, got 1"},
+		{"crosses_above(price)", "col 1: crosses_above expects 2 to 3 arguments, got 1"},
+		{"abs(price > 1) > 1", "col 11: argument 1 of abs must be a number, got bool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Compile(tt.src, testSchema)
+			var exprErr *Error
+			if !errors.As(err, &exprErr) {
+				t.Fatalf("Compile() error = %v, want *Error", err)
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Compile() error = %q, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileIdentifiers(t *testing.T) {
+	p, err := Compile("rsi_14 < 30 && price > sma_20 && rsi_14 > 10", testSchema)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	got := strings.Join(p.Identifiers(), ",")
+	if got != "rsi_14,price,sma_20" {
+		t.Errorf("Identifiers() = %s, want rsi_14,price,sma_20", got)
+	}
+	if p.Stateful() {
+		t.Error("Stateful() = true for a program without edge functions")
+	}
+}
+
+func TestEval(t *testing.T) {
+	vars := VarsFunc(func(name string) (float64, bool) {
+		v, ok := map[string]float64{"price": 105, "rsi_14": 25, "sma_20": 100, "volume": math.NaN()}[name]
+		return v, ok
+	})
+
+	tests := []struct {
+		src     string
+		want    bool
+		falseBy float64
+		err     error
+	}{
+		{src: "price > sma_20", want: true},
+		{src: "price > 110", falseBy: 5},
+		{src: "rsi_14 <= 20", falseBy: 5},
+		{src: "price == 100", falseBy: 5},
+		{src: "price != 105", falseBy: 0},
+		{src: "pct_change(sma_20, price) >= 5", want: true},
+		{src: "abs(-price) == 105 && min(rsi_14, 30) < max(1, 2, 26)", want: true},
+		{src: "price % 10 == 5 && price / 5 - 1 * 2 == 19", want: true},
+		{src: "price > 110 && rsi_14 < 20", falseBy: 5},
+		{src: "price > 110 || rsi_14 < 22", falseBy: 3},
+		{src: "!(price > 110)", want: true},
+		{src: "(price > 1) == true", want: true},
+		{src: "volume > 0", err: ErrUnavailable},
+		{src: "volume > 0 && price > 110", falseBy: 5},
+		{src: "volume > 0 || price > 100", want: true},
+		{src: "volume > 0 && price > 100", err: ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			p, err := Compile(tt.src, testSchema)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			got, err := p.Eval(vars, nil)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Eval() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got.Value != tt.want {
+				t.Errorf("Eval() = %v, want %v", got.Value, tt.want)
+			}
+			if math.Abs(got.FalseBy()-tt.falseBy) > 1e-9 {
+				t.Errorf("FalseBy() = %v, want %v", got.FalseBy(), tt.falseBy)
+			}
+		})
+	}
+}
+
+func TestEvalRuntimeErrors(t *testing.T) {
+	vars := VarsFunc(func(string) (float64, bool) { return 0, true })
+
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"1 / price > 0", "division by zero"},
+		{"1 % price > 0", "division by zero"},
+		{"pct_change(price, 1) > 0", "pct_change: division by zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			p, err := Compile(tt.src, testSchema)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if _, err := p.Eval(vars, nil); err == nil || err.Error() != tt.want {
+				t.Errorf("Eval() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalEdges(t *testing.T) {
+	tests := []struct {
+		src    string
+		prices []float64
+		want   []bool
+	}{
+		{"crosses_above(price, 100)", []float64{99, 101, 102, 99, 101}, []bool{false, true, false, false, true}},
+		{"crosses_above(price, 100)", []float64{101, 102, 99, 101}, []bool{false, false, false, true}},
+		{"crosses_above(price, 100, 2)", []float64{97, 101, 99, 101, 97, 101}, []bool{false, true, false, false, false, true}},
+		{"crosses_below(price, 100)", []float64{101, 99, 98, 101, 99}, []bool{false, true, false, false, true}},
+		{"enters_range(price, 90, 110)", []float64{80, 100, 105, 120, 95}, []bool{false, true, false, false, true}},
+		{"exits_range(price, 90, 110, 5)", []float64{100, 112, 116, 100, 80}, []bool{false, false, true, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			p, err := Compile(tt.src, testSchema)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if _, err := p.Eval(VarsFunc(func(string) (float64, bool) { return 0, true }), nil); err == nil {
+				t.Error("Eval() without state succeeded for a stateful program")
+			}
+
+			state := NewState(p)
+			for i, price := range tt.prices {
+				vars := VarsFunc(func(string) (float64, bool) { return price, true })
+				got, err := p.Eval(vars, state)
+				if err != nil {
+					t.Fatalf("Eval() error = %v", err)
+				}
+				if got.Value != tt.want[i] {
+					t.Errorf("update %d (price %v) = %v, want %v", i, price, got.Value, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// tokenKind identifies the lexical class of a token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokLParen
+	tokRParen
+	tokComma
+	tokPlus
+	tokMinus
+	tokStar
+	tokSlash
+	tokPercent
+	tokLT
+	tokLE
+	tokGT
+	tokGE
+	tokEQ
+	tokNE
+	tokAnd
+	tokOr
+	tokNot
+)
+
+var tokenNames = map[tokenKind]string{
+	tokEOF:     "end of expression",
+	tokNumber:  "number",
+	tokIdent:   "identifier",
+	tokLParen:  "'('",
+	tokRParen:  "')'",
+	tokComma:   "','",
+	tokPlus:    "'+'",
+	tokMinus:   "'-'",
+	tokStar:    "'*'",
+	tokSlash:   "'/'",
+	tokPercent: "'%'",
+	tokLT:      "'<'",
+	tokLE:      "'<='",
+	tokGT:      "'>'",
+	tokGE:      "'>='",
+	tokEQ:      "'=='",
+	tokNE:      "'!='",
+	tokAnd:     "'&&'",
+	tokOr:      "'||'",
+	tokNot:     "'!'",
+}
+
+func (k tokenKind) String() string {
+	if name, ok := tokenNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("token(%d)", int(k))
+}
+
+// token is a single lexical element of an expression
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int // 1-based column
+}
+
+// lex splits an expression into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			num, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, &Error{Pos: pos, Msg: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, num: num, pos: pos})
+			continue
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: string(runes[start:i]), pos: pos})
+			continue
+		}
+
+		// Operators and punctuation
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		kind, width := tokEOF, 1
+		switch r {
+		case '(':
+			kind = tokLParen
+		case ')':
+			kind = tokRParen
+		case ',':
+			kind = tokComma
+		case '+':
+			kind = tokPlus
+		case '-':
+			kind = tokMinus
+		case '*':
+			kind = tokStar
+		case '/':
+			kind = tokSlash
+		case '%':
+			kind = tokPercent
+		case '<':
+			kind = tokLT
+			if next == '=' {
+				kind, width = tokLE, 2
+			}
+		case '>':
+			kind = tokGT
+			if next == '=' {
+				kind, width = tokGE, 2
+			}
+		case '=':
+			if next == '=' {
+				kind, width = tokEQ, 2
+			}
+		case '!':
+			kind = tokNot
+			if next == '=' {
+				kind, width = tokNE, 2
+			}
+		case '&':
+			if next == '&' {
+				kind, width = tokAnd, 2
+			}
+		case '|':
+			if next == '|' {
+				kind, width = tokOr, 2
+			}
+		}
+
+		if kind == tokEOF {
+			return nil, &Error{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+		tokens = append(tokens, token{kind: kind, text: string(runes[i : i+width]), pos: pos})
+		i += width
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
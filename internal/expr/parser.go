@@ -0,0 +1,244 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parser is a recursive-descent parser over a token stream.
+//
+// Grammar, lowest precedence first:
+//
+//	or      = and { "||" and }
+//	and     = not { "&&" not }
+//	not     = "!" not | compare
+//	compare = sum [ ("<" | "<=" | ">" | ">=" | "==" | "!=") sum ]
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/" | "%") unary }
+//	unary   = "-" unary | primary
+//	primary = number | "true" | "false" | ident | ident "(" [ or { "," or } ] ")" | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse turns source text into a syntax tree
+func parse(src string) (node, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, &Error{Pos: 1, Msg: "empty expression"}
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", describe(tok))}
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, &Error{Pos: tok.pos, Msg: fmt.Sprintf("expected %s, found %s", kind, describe(tok))}
+	}
+	return tok, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		op := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{at: op.pos, op: op.kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		op := p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{at: op.pos, op: op.kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().kind == tokNot {
+		op := p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{at: op.pos, op: op.kind, operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	switch p.peek().kind {
+	case tokLT, tokLE, tokGT, tokGE, tokEQ, tokNE:
+		op := p.next()
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		// Comparisons do not chain: "a < b < c" is rejected
+		switch p.peek().kind {
+		case tokLT, tokLE, tokGT, tokGE, tokEQ, tokNE:
+			tok := p.peek()
+			return nil, &Error{Pos: tok.pos, Msg: "comparisons cannot be chained; use &&"}
+		}
+		return &binary{at: op.pos, op: op.kind, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokPlus || p.peek().kind == tokMinus {
+		op := p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{at: op.pos, op: op.kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokStar || p.peek().kind == tokSlash || p.peek().kind == tokPercent {
+		op := p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{at: op.pos, op: op.kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokMinus {
+		op := p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{at: op.pos, op: op.kind, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return &numberLit{at: tok.pos, value: tok.num}, nil
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return &boolLit{at: tok.pos, value: true}, nil
+		case "false":
+			return &boolLit{at: tok.pos, value: false}, nil
+		}
+		if p.peek().kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		return &ident{at: tok.pos, name: strings.ToLower(tok.text)}, nil
+
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	return nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", describe(tok))}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	p.next() // consume "("
+	c := &call{at: name.pos, name: strings.ToLower(name.text)}
+
+	if p.peek().kind == tokRParen {
+		p.next()
+		return c, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		c.args = append(c.args, arg)
+
+		tok := p.next()
+		if tok.kind == tokRParen {
+			return c, nil
+		}
+		if tok.kind != tokComma {
+			return nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("expected ',' or ')', found %s", describe(tok))}
+		}
+	}
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case tokEOF:
+		return tok.kind.String()
+	case tokNumber, tokIdent:
+		return fmt.Sprintf("%s %q", tok.kind, tok.text)
+	default:
+		return tok.kind.String()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"sort"
 	"sync"
 
 	"github.com/IBM/sarama"
@@ -13,20 +14,35 @@ import (
 // MessageHandler is called when a message is received
 type MessageHandler func(ctx context.Context, event interface{}) error
 
+// Topics lists the Kafka topics the consumer subscribes to.
+// Topics left empty are not subscribed.
+type Topics struct {
+	Decision  string // trading.decisions
+	Ranking   string // trading.rankings
+	Quote     string // stock.quotes.realtime
+	Indicator string // stock.indicators
+	Position  string // trading.positions
+}
+
+// route binds a topic to the event type it carries and its handler
+type route struct {
+	name     string
+	newEvent func() interface{}
+	handler  MessageHandler
+}
+
 // Consumer wraps Sarama consumer group for Kafka consumption
 type Consumer struct {
-	client           sarama.ConsumerGroup
-	decisionTopic    string
-	rankingTopic     string
-	decisionHandler  MessageHandler
-	rankingHandler   MessageHandler
-	ready            chan bool
-	cancel           context.CancelFunc
-	wg               sync.WaitGroup
+	client sarama.ConsumerGroup
+	topics Topics
+	routes map[string]*route
+	ready  chan bool
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // NewConsumer creates a new Kafka consumer
-func NewConsumer(brokers []string, groupID, decisionTopic, rankingTopic string) (*Consumer, error) {
+func NewConsumer(brokers []string, groupID string, topics Topics) (*Consumer, error) {
 	config := sarama.NewConfig()
 	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
 	config.Consumer.Offsets.Initial = sarama.OffsetNewest
@@ -38,28 +54,59 @@ func NewConsumer(brokers []string, groupID, decisionTopic, rankingTopic string)
 	}
 
 	return &Consumer{
-		client:        client,
-		decisionTopic: decisionTopic,
-		rankingTopic:  rankingTopic,
-		ready:         make(chan bool),
+		client: client,
+		topics: topics,
+		routes: make(map[string]*route),
+		ready:  make(chan bool),
 	}, nil
 }
 
 // SetDecisionHandler sets the handler for decision events
 func (c *Consumer) SetDecisionHandler(handler MessageHandler) {
-	c.decisionHandler = handler
+	c.setRoute(c.topics.Decision, "decision", func() interface{} { return &models.DecisionEvent{} }, handler)
 }
 
 // SetRankingHandler sets the handler for ranking events
 func (c *Consumer) SetRankingHandler(handler MessageHandler) {
-	c.rankingHandler = handler
+	c.setRoute(c.topics.Ranking, "ranking", func() interface{} { return &models.RankingEvent{} }, handler)
+}
+
+// SetQuoteHandler sets the handler for real-time quote events
+func (c *Consumer) SetQuoteHandler(handler MessageHandler) {
+	c.setRoute(c.topics.Quote, "quote", func() interface{} { return &models.QuoteEvent{} }, handler)
+}
+
+// SetIndicatorHandler sets the handler for indicator events
+func (c *Consumer) SetIndicatorHandler(handler MessageHandler) {
+	c.setRoute(c.topics.Indicator, "indicator", func() interface{} { return &models.IndicatorEvent{} }, handler)
 }
 
-// Start begins consuming messages from both topics
+// SetPositionHandler sets the handler for position events
+func (c *Consumer) SetPositionHandler(handler MessageHandler) {
+	c.setRoute(c.topics.Position, "position", func() interface{} { return &models.PositionEvent{} }, handler)
+}
+
+func (c *Consumer) setRoute(topic, name string, newEvent func() interface{}, handler MessageHandler) {
+	if topic == "" {
+		return
+	}
+	c.routes[topic] = &route{
+		name:     name,
+		newEvent: newEvent,
+		handler:  handler,
+	}
+}
+
+// Start begins consuming messages from all topics with a handler
 func (c *Consumer) Start(ctx context.Context) error {
 	ctx, c.cancel = context.WithCancel(ctx)
 
-	topics := []string{c.decisionTopic, c.rankingTopic}
+	topics := make([]string, 0, len(c.routes))
+	for topic := range c.routes {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	log.Printf("Subscribing to Kafka topics: %v", topics)
 
 	c.wg.Add(1)
 	go func() {
@@ -122,33 +169,16 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 			ctx := session.Context()
 
 			// Determine message type based on topic
-			switch message.Topic {
-			case h.consumer.decisionTopic:
-				if h.consumer.decisionHandler != nil {
-					var event models.DecisionEvent
-					if err := json.Unmarshal(message.Value, &event); err != nil {
-						log.Printf("Failed to unmarshal decision event: %v", err)
-						session.MarkMessage(message, "")
-						continue
-					}
-
-					if err := h.consumer.decisionHandler(ctx, &event); err != nil {
-						log.Printf("Failed to handle decision event: %v", err)
-					}
+			if r, ok := h.consumer.routes[message.Topic]; ok && r.handler != nil {
+				event := r.newEvent()
+				if err := json.Unmarshal(message.Value, event); err != nil {
+					log.Printf("Failed to unmarshal %s event: %v", r.name, err)
+					session.MarkMessage(message, "")
+					continue
 				}
 
-			case h.consumer.rankingTopic:
-				if h.consumer.rankingHandler != nil {
-					var event models.RankingEvent
-					if err := json.Unmarshal(message.Value, &event); err != nil {
-						log.Printf("Failed to unmarshal ranking event: %v", err)
-						session.MarkMessage(message, "")
-						continue
-					}
-
-					if err := h.consumer.rankingHandler(ctx, &event); err != nil {
-						log.Printf("Failed to handle ranking event: %v", err)
-					}
+				if err := r.handler(ctx, event); err != nil {
+					log.Printf("Failed to handle %s event: %v", r.name, err)
 				}
 			}
 
@@ -0,0 +1,213 @@
+package market
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+// Snapshot is a point-in-time copy of the market data known for a symbol
+type Snapshot struct {
+	Symbol             string
+	Price              float64
+	Volume             float64
+	Bid                float64
+	Ask                float64
+	Indicators         map[string]float64
+	QuoteUpdatedAt     time.Time
+	IndicatorUpdatedAt time.Time
+}
+
+// UpdatedAt returns the time of the most recent quote or indicator update
+func (s Snapshot) UpdatedAt() time.Time {
+	if s.IndicatorUpdatedAt.After(s.QuoteUpdatedAt) {
+		return s.IndicatorUpdatedAt
+	}
+	return s.QuoteUpdatedAt
+}
+
+// HasQuote reports whether a price has been received for the symbol
+func (s Snapshot) HasQuote() bool {
+	return !s.QuoteUpdatedAt.IsZero()
+}
+
+// IsStale reports whether the snapshot's quote is older than maxAge.
+// A snapshot without any quote is always stale. A non-positive maxAge
+// disables staleness detection.
+func (s Snapshot) IsStale(now time.Time, maxAge time.Duration) bool {
+	if !s.HasQuote() {
+		return true
+	}
+	if maxAge <= 0 {
+		return false
+	}
+	return now.Sub(s.QuoteUpdatedAt) > maxAge
+}
+
+// Indicator returns the named indicator value, if present
+func (s Snapshot) Indicator(name string) (float64, bool) {
+	v, ok := s.Indicators[strings.ToLower(name)]
+	return v, ok
+}
+
+// State maintains the latest quote and indicators for every symbol seen
+// on the market data topics. It is safe for concurrent use.
+type State struct {
+	mu      sync.RWMutex
+	symbols map[string]*Snapshot
+	maxAge  time.Duration
+}
+
+// NewState creates an empty market state. Snapshots older than maxAge are
+// reported as stale.
+func NewState(maxAge time.Duration) *State {
+	return &State{
+		symbols: make(map[string]*Snapshot),
+		maxAge:  maxAge,
+	}
+}
+
+// MaxAge returns the staleness threshold
+func (st *State) MaxAge() time.Duration {
+	return st.maxAge
+}
+
+// UpdateQuote applies a quote to the symbol's snapshot. Quotes older than
+// the one already held are ignored so out-of-order delivery cannot rewind
+// the price.
+func (st *State) UpdateQuote(q models.QuoteData) bool {
+	symbol := normalizeSymbol(q.Symbol)
+	if symbol == "" {
+		return false
+	}
+	ts := q.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	snap := st.getOrCreate(symbol)
+	if ts.Before(snap.QuoteUpdatedAt) {
+		return false
+	}
+	snap.Price = q.Price
+	snap.Volume = q.Volume
+	snap.Bid = q.Bid
+	snap.Ask = q.Ask
+	snap.QuoteUpdatedAt = ts
+	return true
+}
+
+// UpdateIndicators merges indicator values into the symbol's snapshot.
+// Indicator names are stored lower-cased.
+func (st *State) UpdateIndicators(d models.IndicatorData) bool {
+	symbol := normalizeSymbol(d.Symbol)
+	if symbol == "" {
+		return false
+	}
+	ts := d.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	snap := st.getOrCreate(symbol)
+	if ts.Before(snap.IndicatorUpdatedAt) {
+		return false
+	}
+	for name, value := range d.Indicators {
+		snap.Indicators[strings.ToLower(name)] = value
+	}
+	snap.IndicatorUpdatedAt = ts
+	return true
+}
+
+// Get returns a copy of the symbol's snapshot
+func (st *State) Get(symbol string) (Snapshot, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	snap, ok := st.symbols[normalizeSymbol(symbol)]
+	if !ok {
+		return Snapshot{}, false
+	}
+	return snap.clone(), true
+}
+
+// GetFresh returns the symbol's snapshot only if its quote is not stale
+func (st *State) GetFresh(symbol string) (Snapshot, bool) {
+	snap, ok := st.Get(symbol)
+	if !ok || snap.IsStale(time.Now(), st.maxAge) {
+		return Snapshot{}, false
+	}
+	return snap, true
+}
+
+// IsStale reports whether the symbol's data is missing or older than the
+// staleness threshold
+func (st *State) IsStale(symbol string) bool {
+	snap, ok := st.Get(symbol)
+	return !ok || snap.IsStale(time.Now(), st.maxAge)
+}
+
+// Symbols returns all symbols with market data, sorted
+func (st *State) Symbols() []string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	symbols := make([]string, 0, len(st.symbols))
+	for symbol := range st.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// StaleSymbols returns the symbols whose data is older than the staleness threshold
+func (st *State) StaleSymbols() []string {
+	now := time.Now()
+
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	var stale []string
+	for symbol, snap := range st.symbols {
+		if snap.IsStale(now, st.maxAge) {
+			stale = append(stale, symbol)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func (st *State) getOrCreate(symbol string) *Snapshot {
+	snap, ok := st.symbols[symbol]
+	if !ok {
+		snap = &Snapshot{
+			Symbol:     symbol,
+			Indicators: make(map[string]float64),
+		}
+		st.symbols[symbol] = snap
+	}
+	return snap
+}
+
+func (s *Snapshot) clone() Snapshot {
+	c := *s
+	c.Indicators = make(map[string]float64, len(s.Indicators))
+	for k, v := range s.Indicators {
+		c.Indicators[k] = v
+	}
+	return c
+}
+
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
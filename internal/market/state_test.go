@@ -0,0 +1,121 @@
+package market
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+func TestUpdateQuoteIgnoresOlderQuotes(t *testing.T) {
+	st := NewState(time.Minute)
+	now := time.Now()
+
+	if !st.UpdateQuote(models.QuoteData{Symbol: " aapl ", Price: 101, Timestamp: now}) {
+		t.Fatal("first quote was not applied")
+	}
+	if st.UpdateQuote(models.QuoteData{Symbol: "AAPL", Price: 99, Timestamp: now.Add(-time.Second)}) {
+		t.Error("older quote was applied")
+	}
+	if !st.UpdateQuote(models.QuoteData{Symbol: "AAPL", Price: 102, Volume: 500, Timestamp: now}) {
+		t.Error("quote with the same timestamp was not applied")
+	}
+	if st.UpdateQuote(models.QuoteData{Symbol: "  ", Price: 1, Timestamp: now}) {
+		t.Error("quote without a symbol was applied")
+	}
+
+	snap, ok := st.Get("Aapl")
+	if !ok {
+		t.Fatal("no snapshot for AAPL")
+	}
+	if snap.Symbol != "AAPL" || snap.Price != 102 || snap.Volume != 500 || !snap.QuoteUpdatedAt.Equal(now) {
+		t.Errorf("snapshot = %+v, want AAPL at 102 with volume 500", snap)
+	}
+	if got := st.Symbols(); len(got) != 1 || got[0] != "AAPL" {
+		t.Errorf("Symbols() = %v, want [AAPL]", got)
+	}
+}
+
+func TestUpdateIndicators(t *testing.T) {
+	st := NewState(time.Minute)
+	now := time.Now()
+
+	st.UpdateIndicators(models.IndicatorData{Symbol: "msft", Timestamp: now,
+		Indicators: map[string]float64{"RSI_14": 28, "SMA_20": 410}})
+	st.UpdateIndicators(models.IndicatorData{Symbol: "MSFT", Timestamp: now.Add(time.Second),
+		Indicators: map[string]float64{"rsi_14": 31}})
+	if st.UpdateIndicators(models.IndicatorData{Symbol: "MSFT", Timestamp: now.Add(-time.Minute),
+		Indicators: map[string]float64{"rsi_14": 10, "macd": 1}}) {
+		t.Error("older indicators were applied")
+	}
+
+	snap, _ := st.Get("MSFT")
+	want := map[string]float64{"rsi_14": 31, "sma_20": 410}
+	if len(snap.Indicators) != len(want) {
+		t.Fatalf("indicators = %v, want %v", snap.Indicators, want)
+	}
+	for name, value := range want {
+		if got, ok := snap.Indicator(name); !ok || got != value {
+			t.Errorf("Indicator(%s) = %v, %v; want %v", name, got, ok, value)
+		}
+	}
+	if got, ok := snap.Indicator("RSI_14"); !ok || got != 31 {
+		t.Errorf("Indicator(RSI_14) = %v, %v; want 31", got, ok)
+	}
+
+	// Snapshots are copies
+	snap.Indicators["rsi_14"] = 99
+	if again, _ := st.Get("MSFT"); again.Indicators["rsi_14"] != 31 {
+		t.Error("changing a snapshot changed the state")
+	}
+
+	// Indicators alone are not a quote
+	if !snap.IsStale(now, time.Hour) || !st.IsStale("MSFT") {
+		t.Error("snapshot without a quote is not stale")
+	}
+	if !snap.UpdatedAt().Equal(now.Add(time.Second)) {
+		t.Errorf("UpdatedAt() = %s, want the latest indicator update", snap.UpdatedAt())
+	}
+}
+
+func TestStaleness(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		quote  time.Time
+		maxAge time.Duration
+		stale  bool
+	}{
+		{"no quote", time.Time{}, time.Minute, true},
+		{"fresh", now.Add(-30 * time.Second), time.Minute, false},
+		{"at the limit", now.Add(-time.Minute), time.Minute, false},
+		{"stale", now.Add(-61 * time.Second), time.Minute, true},
+		{"staleness disabled", now.Add(-24 * time.Hour), 0, false},
+		{"staleness disabled without a quote", time.Time{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := Snapshot{Symbol: "AAPL", QuoteUpdatedAt: tt.quote}
+			if got := snap.IsStale(now, tt.maxAge); got != tt.stale {
+				t.Errorf("IsStale() = %v, want %v", got, tt.stale)
+			}
+		})
+	}
+
+	st := NewState(time.Minute)
+	st.UpdateQuote(models.QuoteData{Symbol: "OLD", Price: 1, Timestamp: now.Add(-time.Hour)})
+	st.UpdateQuote(models.QuoteData{Symbol: "NEW", Price: 1, Timestamp: now})
+	if got := st.StaleSymbols(); len(got) != 1 || got[0] != "OLD" {
+		t.Errorf("StaleSymbols() = %v, want [OLD]", got)
+	}
+	if _, ok := st.GetFresh("OLD"); ok {
+		t.Error("GetFresh returned a stale snapshot")
+	}
+	if _, ok := st.GetFresh("new"); !ok {
+		t.Error("GetFresh did not return a fresh snapshot")
+	}
+	if _, ok := st.Get("NONE"); ok || !st.IsStale("NONE") {
+		t.Error("unknown symbol has a snapshot or is not stale")
+	}
+}
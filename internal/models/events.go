@@ -4,11 +4,11 @@ import "time"
 
 // DecisionEvent represents a trading decision from the decision-engine
 type DecisionEvent struct {
-	EventType     string        `json:"event_type"`
-	Source        string        `json:"source"`
-	SchemaVersion string        `json:"schema_version"`
-	Timestamp     time.Time     `json:"timestamp"`
-	Data          DecisionData  `json:"data"`
+	EventType     string       `json:"event_type"`
+	Source        string       `json:"source"`
+	SchemaVersion string       `json:"schema_version"`
+	Timestamp     time.Time    `json:"timestamp"`
+	Data          DecisionData `json:"data"`
 }
 
 // DecisionData contains the actual decision information
@@ -31,11 +31,11 @@ type RuleResult struct {
 
 // RankingEvent represents a ranking update from the decision-engine
 type RankingEvent struct {
-	EventType     string       `json:"event_type"`
-	Source        string       `json:"source"`
-	SchemaVersion string       `json:"schema_version"`
-	Timestamp     time.Time    `json:"timestamp"`
-	Data          RankingData  `json:"data"`
+	EventType     string      `json:"event_type"`
+	Source        string      `json:"source"`
+	SchemaVersion string      `json:"schema_version"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Data          RankingData `json:"data"`
 }
 
 // RankingData contains the ranking information
@@ -64,3 +64,62 @@ const (
 	SignalSell  = "SELL"
 	SignalWatch = "WATCH"
 )
+
+// QuoteEvent represents a real-time price update from stock.quotes.realtime
+type QuoteEvent struct {
+	EventType     string    `json:"event_type"`
+	Source        string    `json:"source"`
+	SchemaVersion string    `json:"schema_version"`
+	Timestamp     time.Time `json:"timestamp"`
+	Data          QuoteData `json:"data"`
+}
+
+// QuoteData contains the quote information
+type QuoteData struct {
+	Symbol    string    `json:"symbol"`
+	Price     float64   `json:"price"`
+	Volume    float64   `json:"volume"`
+	Bid       float64   `json:"bid,omitempty"`
+	Ask       float64   `json:"ask,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IndicatorEvent represents a technical indicator update from stock.indicators
+type IndicatorEvent struct {
+	EventType     string        `json:"event_type"`
+	Source        string        `json:"source"`
+	SchemaVersion string        `json:"schema_version"`
+	Timestamp     time.Time     `json:"timestamp"`
+	Data          IndicatorData `json:"data"`
+}
+
+// IndicatorData contains the indicator values for a symbol
+type IndicatorData struct {
+	Symbol     string             `json:"symbol"`
+	Timeframe  string             `json:"timeframe,omitempty"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Indicators map[string]float64 `json:"indicators"`
+}
+
+// PositionEvent represents a position update from trading.positions
+type PositionEvent struct {
+	EventType     string        `json:"event_type"`
+	Source        string        `json:"source"`
+	SchemaVersion string        `json:"schema_version"`
+	Timestamp     time.Time     `json:"timestamp"`
+	Data          PositionsData `json:"data"`
+}
+
+// PositionsData carries changed positions, or every position when Snapshot
+// is set. A zero quantity closes a position.
+type PositionsData struct {
+	Snapshot  bool           `json:"snapshot"`
+	Positions []PositionData `json:"positions"`
+}
+
+// PositionData is the holding in one symbol
+type PositionData struct {
+	Symbol    string  `json:"symbol"`
+	Quantity  float64 `json:"quantity"`
+	CostBasis float64 `json:"cost_basis"` // average cost per share
+}
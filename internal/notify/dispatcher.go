@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Notifier delivers a rendered alert message over one channel
+type Notifier interface {
+	Name() string
+	SendMessage(ctx context.Context, message string) error
+}
+
+// Delivery statuses
+const (
+	StatusSent   = "sent"
+	StatusFailed = "failed"
+)
+
+// Delivery is the outcome of sending a message over one channel
+type Delivery struct {
+	Channel string `json:"channel"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Dispatcher fans messages out to named notification channels
+type Dispatcher struct {
+	notifiers map[string]Notifier
+	defaults  []string
+}
+
+// NewDispatcher creates a dispatcher. Messages sent without explicit
+// channels go to every notifier given here.
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	d := &Dispatcher{notifiers: make(map[string]Notifier)}
+	for _, n := range notifiers {
+		d.notifiers[strings.ToLower(n.Name())] = n
+		d.defaults = append(d.defaults, strings.ToLower(n.Name()))
+	}
+	return d
+}
+
+// DefaultChannels returns the channels used when none are requested
+func (d *Dispatcher) DefaultChannels() []string {
+	return append([]string(nil), d.defaults...)
+}
+
+// Send delivers a message to each channel, or to the default channels if
+// none are given. Every channel is attempted; the returned error joins the
+// failures and the deliveries report each channel's outcome.
+func (d *Dispatcher) Send(ctx context.Context, message string, channels []string) ([]Delivery, error) {
+	if len(channels) == 0 {
+		channels = d.defaults
+	}
+
+	deliveries := make([]Delivery, 0, len(channels))
+	var errs []error
+	for _, channel := range channels {
+		name := strings.ToLower(channel)
+		n, ok := d.notifiers[name]
+		if !ok {
+			err := fmt.Errorf("unknown notification channel %q", channel)
+			deliveries = append(deliveries, Delivery{Channel: name, Status: StatusFailed, Error: err.Error()})
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := n.SendMessage(ctx, message); err != nil {
+			deliveries = append(deliveries, Delivery{Channel: name, Status: StatusFailed, Error: err.Error()})
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		deliveries = append(deliveries, Delivery{Channel: name, Status: StatusSent})
+	}
+
+	return deliveries, errors.Join(errs...)
+}
@@ -0,0 +1,114 @@
+package portfolio
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Position is the holding in one symbol. CostBasis is the average cost
+// per share; a negative quantity is a short position.
+type Position struct {
+	Symbol    string    `json:"symbol"`
+	Quantity  float64   `json:"quantity"`
+	CostBasis float64   `json:"cost_basis"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Cost returns the total cost of the position
+func (p Position) Cost() float64 {
+	return p.Quantity * p.CostBasis
+}
+
+// UnrealizedPnL returns the gain of the position at price, in dollars and
+// as a fraction of its cost
+func (p Position) UnrealizedPnL(price float64) (float64, float64) {
+	pnl := p.Quantity * (price - p.CostBasis)
+	if p.Cost() == 0 {
+		return pnl, 0
+	}
+	if p.Quantity < 0 {
+		return pnl, pnl / -p.Cost()
+	}
+	return pnl, pnl / p.Cost()
+}
+
+// Book holds the current positions. Until a full set is first loaded it
+// reports itself as not loaded, so callers can tell "holds nothing" from
+// "does not know yet". It is safe for concurrent use.
+type Book struct {
+	mu        sync.RWMutex
+	positions map[string]Position
+	loaded    bool
+}
+
+// NewBook creates an empty, not yet loaded book
+func NewBook() *Book {
+	return &Book{positions: make(map[string]Position)}
+}
+
+// Replace swaps in a full set of positions
+func (b *Book) Replace(positions []Position) {
+	next := make(map[string]Position, len(positions))
+	for _, p := range positions {
+		if p, ok := normalize(p); ok {
+			next[p.Symbol] = p
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.positions = next
+	b.loaded = true
+}
+
+// Update sets one position; a zero quantity closes it. A single update does
+// not make the book loaded: only a full snapshot shows what is not held.
+func (b *Book) Update(p Position) {
+	p, ok := normalize(p)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		b.positions[p.Symbol] = p
+	} else {
+		delete(b.positions, p.Symbol)
+	}
+}
+
+// Get returns the open position in a symbol
+func (b *Book) Get(symbol string) (Position, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	p, ok := b.positions[strings.ToUpper(strings.TrimSpace(symbol))]
+	return p, ok
+}
+
+// Loaded reports whether positions have been received
+func (b *Book) Loaded() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.loaded
+}
+
+// Positions returns the open positions sorted by symbol
+func (b *Book) Positions() []Position {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	list := make([]Position, 0, len(b.positions))
+	for _, p := range b.positions {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Symbol < list[j].Symbol })
+	return list
+}
+
+// normalize upper-cases the symbol and reports whether the position is open
+func normalize(p Position) (Position, bool) {
+	p.Symbol = strings.ToUpper(strings.TrimSpace(p.Symbol))
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = time.Now()
+	}
+	return p, p.Symbol != "" && p.Quantity != 0
+}
@@ -0,0 +1,88 @@
+package portfolio
+
+import "testing"
+
+func TestBookLoaded(t *testing.T) {
+	tests := []struct {
+		name   string
+		apply  func(b *Book)
+		loaded bool
+		held   []string
+	}{
+		{
+			name:   "new book",
+			apply:  func(b *Book) {},
+			loaded: false,
+		},
+		{
+			name:   "updates alone",
+			apply:  func(b *Book) { b.Update(Position{Symbol: "aapl", Quantity: 10, CostBasis: 150}) },
+			loaded: false,
+			held:   []string{"AAPL"},
+		},
+		{
+			name:   "empty snapshot",
+			apply:  func(b *Book) { b.Replace(nil) },
+			loaded: true,
+		},
+		{
+			name: "snapshot then updates",
+			apply: func(b *Book) {
+				b.Replace([]Position{{Symbol: "AAPL", Quantity: 10}, {Symbol: "MSFT", Quantity: 5}})
+				b.Update(Position{Symbol: "MSFT", Quantity: 0})
+				b.Update(Position{Symbol: " tsla ", Quantity: -3, CostBasis: 200})
+			},
+			loaded: true,
+			held:   []string{"AAPL", "TSLA"},
+		},
+		{
+			name: "snapshot replaces updates",
+			apply: func(b *Book) {
+				b.Update(Position{Symbol: "AAPL", Quantity: 10})
+				b.Replace([]Position{{Symbol: "MSFT", Quantity: 5}, {Symbol: "GLD", Quantity: 0}})
+			},
+			loaded: true,
+			held:   []string{"MSFT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBook()
+			tt.apply(b)
+			if b.Loaded() != tt.loaded {
+				t.Errorf("Loaded() = %v, want %v", b.Loaded(), tt.loaded)
+			}
+			var held []string
+			for _, p := range b.Positions() {
+				held = append(held, p.Symbol)
+			}
+			if len(held) != len(tt.held) {
+				t.Fatalf("positions = %v, want %v", held, tt.held)
+			}
+			for i := range held {
+				if held[i] != tt.held[i] {
+					t.Errorf("positions = %v, want %v", held, tt.held)
+				}
+			}
+		})
+	}
+}
+
+func TestUnrealizedPnL(t *testing.T) {
+	tests := []struct {
+		position Position
+		price    float64
+		pnl, pct float64
+	}{
+		{Position{Quantity: 10, CostBasis: 100}, 110, 100, 0.1},
+		{Position{Quantity: -10, CostBasis: 100}, 90, 100, 0.1},
+		{Position{Quantity: 10}, 5, 50, 0},
+	}
+	for _, tt := range tests {
+		pnl, pct := tt.position.UnrealizedPnL(tt.price)
+		if pnl != tt.pnl || pct != tt.pct {
+			t.Errorf("%+v at %.2f: UnrealizedPnL() = %.2f, %.3f; want %.2f, %.3f", tt.position, tt.price, pnl, pct, tt.pnl, tt.pct)
+		}
+	}
+}
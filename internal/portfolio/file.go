@@ -0,0 +1,90 @@
+package portfolio
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// parse reads a positions file holding either a list of positions or an
+// object with a "positions" list
+func parse(path string, raw []byte) ([]Position, error) {
+	var positions []Position
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &positions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse positions file %s: %w", path, err)
+		}
+		return positions, nil
+	}
+
+	var file struct {
+		Positions []Position `json:"positions"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse positions file %s: %w", path, err)
+	}
+	return file.Positions, nil
+}
+
+// FileLoader keeps a book in sync with a positions file, reloading it
+// when its contents change
+type FileLoader struct {
+	path     string
+	book     *Book
+	interval time.Duration
+	hash     [sha256.Size]byte
+}
+
+// NewFileLoader creates a loader that checks the file every interval
+func NewFileLoader(path string, book *Book, interval time.Duration) *FileLoader {
+	return &FileLoader{path: path, book: book, interval: interval}
+}
+
+// Reload reads the file and replaces the book's positions if it changed
+func (l *FileLoader) Reload() error {
+	raw, err := os.ReadFile(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to read positions file: %w", err)
+	}
+	hash := sha256.Sum256(raw)
+	if l.book.Loaded() && hash == l.hash {
+		return nil
+	}
+
+	positions, err := parse(l.path, raw)
+	if err != nil {
+		return err
+	}
+	l.book.Replace(positions)
+	l.hash = hash
+	log.Printf("Loaded %d position(s) from %s", len(l.book.Positions()), l.path)
+	return nil
+}
+
+// Run reloads the file every interval until the context is cancelled. A
+// file that fails to load keeps the previous positions.
+func (l *FileLoader) Run(ctx context.Context) {
+	if l.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Reload(); err != nil {
+				log.Printf("Failed to reload positions: %v", err)
+			}
+		}
+	}
+}
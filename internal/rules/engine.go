@@ -0,0 +1,284 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/trogers1052/alert-service/internal/expr"
+	"github.com/trogers1052/alert-service/internal/market"
+)
+
+// LoadError lists the rules that failed validation when a rule set was loaded
+type LoadError struct {
+	Errors []error
+}
+
+func (e *LoadError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d invalid rule(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Trigger is a rule whose condition held for a symbol
+type Trigger struct {
+	Rule      Rule
+	Condition string // evaluated condition, derived from params for fixed rule types
+	Symbol    string
+	Snapshot  market.Snapshot
+	Fields    []string           // identifiers referenced by the condition, in order
+	Values    map[string]float64 // current value of each referenced identifier
+}
+
+// compiledRule is a validated rule ready for evaluation
+type compiledRule struct {
+	rule      Rule
+	condition string
+	program   *expr.Program
+	symbols   map[string]bool // nil matches every symbol
+}
+
+func (c *compiledRule) appliesTo(symbol string) bool {
+	return c.symbols == nil || c.symbols[symbol]
+}
+
+// ruleState is the memory kept for one rule on one symbol between evaluations
+type ruleState struct {
+	condition  string      // condition the state was built for
+	trigger    string      // trigger mode the state was built for
+	hysteresis float64     // hysteresis the state was built for
+	expr       *expr.State // edge-triggered function memory
+	armed      bool        // edge rules: fire on true evaluations until acknowledged
+}
+
+// Engine evaluates custom rule conditions against market snapshots.
+// It is safe for concurrent use.
+type Engine struct {
+	mu     sync.RWMutex
+	rules  []*compiledRule
+	states map[string]*ruleState // rule ID + symbol -> state
+}
+
+// NewEngine creates an engine with no rules
+func NewEngine() *Engine {
+	return &Engine{states: make(map[string]*ruleState)}
+}
+
+// Load compiles and installs a rule set, replacing the current rules.
+// Disabled rules are skipped.
+// Every condition is parsed and type-checked here; rules that fail are
+// left out and reported in the returned *LoadError while the valid rules
+// are still installed.
+func (e *Engine) Load(set *RuleSet) error {
+	schema := NewSchema(set.Indicators)
+
+	var compiled []*compiledRule
+	var errs []error
+	seen := make(map[string]bool)
+
+	for _, rule := range set.Rules {
+		if !rule.IsEnabled() {
+			continue
+		}
+		c, err := compileRule(rule, set.Groups, schema)
+		if err == nil && seen[rule.ID] {
+			err = fmt.Errorf("duplicate rule id")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", rule.ID, err))
+			continue
+		}
+		seen[rule.ID] = true
+		compiled = append(compiled, c)
+	}
+
+	e.mu.Lock()
+	e.rules = compiled
+	e.pruneStates()
+	e.mu.Unlock()
+
+	if len(errs) > 0 {
+		return &LoadError{Errors: errs}
+	}
+	return nil
+}
+
+func compileRule(rule Rule, groups map[string][]string, schema *Schema) (*compiledRule, error) {
+	if rule.ID == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+
+	switch rule.TriggerMode() {
+	case TriggerLevel, TriggerEdge:
+	default:
+		return nil, fmt.Errorf("unknown trigger %q (want %s or %s)", rule.Trigger, TriggerLevel, TriggerEdge)
+	}
+	if rule.Hysteresis < 0 {
+		return nil, fmt.Errorf("hysteresis must not be negative")
+	}
+
+	condition, err := rule.Expression()
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := expr.Compile(condition, schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+
+	c := &compiledRule{rule: rule, condition: condition, program: program}
+
+	symbols := rule.Symbols
+	if rule.Group != "" {
+		members, ok := groups[rule.Group]
+		if !ok {
+			return nil, fmt.Errorf("unknown symbol group %q", rule.Group)
+		}
+		symbols = append(append([]string(nil), symbols...), members...)
+	}
+	if len(symbols) > 0 {
+		c.symbols = make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			c.symbols[strings.ToUpper(strings.TrimSpace(symbol))] = true
+		}
+	}
+
+	return c, nil
+}
+
+// Rules returns the currently installed rules
+func (e *Engine) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	out := make([]Rule, len(e.rules))
+	for i, c := range e.rules {
+		out[i] = c.rule
+	}
+	return out
+}
+
+// pruneStates drops state for rules that were removed or whose condition,
+// trigger mode or hysteresis changed. Callers must hold e.mu.
+func (e *Engine) pruneStates() {
+	rules := make(map[string]*compiledRule, len(e.rules))
+	for _, c := range e.rules {
+		rules[c.rule.ID] = c
+	}
+	for key, st := range e.states {
+		c, ok := rules[key[:strings.LastIndex(key, "|")]]
+		if !ok || !st.builtFor(c) {
+			delete(e.states, key)
+		}
+	}
+}
+
+// builtFor reports whether the state was built for the rule as compiled now
+func (st *ruleState) builtFor(c *compiledRule) bool {
+	return st.condition == c.condition && st.trigger == c.rule.TriggerMode() && st.hysteresis == c.rule.Hysteresis
+}
+
+// stateFor returns the rule's state for a symbol, creating it disarmed:
+// like crosses_above, an edge rule needs an observation of its condition
+// being false before it can fire, so a start or reload does not fire it.
+// Callers must hold e.mu for writing.
+func (e *Engine) stateFor(c *compiledRule, symbol string) *ruleState {
+	key := stateKey(c.rule.ID, symbol)
+	st, ok := e.states[key]
+	if !ok {
+		st = &ruleState{
+			condition:  c.condition,
+			trigger:    c.rule.TriggerMode(),
+			hysteresis: c.rule.Hysteresis,
+			expr:       expr.NewState(c.program),
+		}
+		e.states[key] = st
+	}
+	return st
+}
+
+func stateKey(ruleID, symbol string) string {
+	return ruleID + "|" + symbol
+}
+
+// Evaluate returns the rules that fire for the snapshot. Level rules fire
+// whenever their condition holds; edge rules fire on the transition to
+// true and keep firing while it holds until the alert is acknowledged with
+// Ack. Rules referencing values that are not yet known are skipped.
+func (e *Engine) Evaluate(snap market.Snapshot) []Trigger {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	vars := SnapshotVars(snap)
+
+	var triggers []Trigger
+	for _, c := range e.rules {
+		if !c.appliesTo(snap.Symbol) {
+			continue
+		}
+
+		st := e.stateFor(c, snap.Symbol)
+		result, err := c.program.Eval(vars, st.expr)
+		if err != nil {
+			if !errors.Is(err, expr.ErrUnavailable) {
+				log.Printf("Rule %s failed to evaluate for %s: %v", c.rule.ID, snap.Symbol, err)
+			}
+			continue
+		}
+		if !st.fires(c.rule, result) {
+			continue
+		}
+
+		fields := c.program.Identifiers()
+		values := make(map[string]float64, len(fields))
+		for _, name := range fields {
+			if v, ok := vars.Number(name); ok {
+				values[name] = v
+			}
+		}
+		triggers = append(triggers, Trigger{
+			Rule:      c.rule,
+			Condition: c.condition,
+			Symbol:    snap.Symbol,
+			Snapshot:  snap,
+			Fields:    fields,
+			Values:    values,
+		})
+	}
+	return triggers
+}
+
+// fires applies the rule's trigger mode to an evaluation result
+func (st *ruleState) fires(rule Rule, result expr.Result) bool {
+	if rule.TriggerMode() != TriggerEdge {
+		return result.Value
+	}
+
+	if result.Value {
+		return st.armed
+	}
+
+	// Arm only once the condition is false by the hysteresis margin
+	if !st.armed && result.FalseBy() >= rule.Hysteresis {
+		st.armed = true
+	}
+	return false
+}
+
+// Ack disarms an edge rule for a symbol once its alert was delivered or
+// queued. Until then the rule keeps firing while its condition holds, so
+// a fire held back by a cooldown, mute or session filter is not lost.
+// Level rules and unknown rules are ignored.
+func (e *Engine) Ack(ruleID, symbol string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if st, ok := e.states[stateKey(ruleID, symbol)]; ok {
+		st.armed = false
+	}
+}
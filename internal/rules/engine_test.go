@@ -0,0 +1,164 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/market"
+)
+
+func rsiSnapshot(rsi float64) market.Snapshot {
+	return market.Snapshot{
+		Symbol:         "AAPL",
+		Price:          100,
+		QuoteUpdatedAt: time.Now(),
+		Indicators:     map[string]float64{"rsi_14": rsi},
+	}
+}
+
+func TestEngineTriggerModes(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		rsi   []float64
+		fires []bool
+	}{
+		{
+			name:  "level fires while the condition holds",
+			rule:  Rule{ID: "r", Condition: "rsi_14 < 30"},
+			rsi:   []float64{25, 28, 35, 20},
+			fires: []bool{true, true, false, true},
+		},
+		{
+			name:  "edge starts disarmed",
+			rule:  Rule{ID: "r", Condition: "rsi_14 < 30", Trigger: TriggerEdge},
+			rsi:   []float64{25, 28, 35, 25, 20},
+			fires: []bool{false, false, false, true, false},
+		},
+		{
+			name:  "edge re-arms only past the hysteresis",
+			rule:  Rule{ID: "r", Condition: "rsi_14 < 30", Trigger: TriggerEdge, Hysteresis: 5},
+			rsi:   []float64{40, 25, 32, 25, 36, 25},
+			fires: []bool{false, true, false, false, false, true},
+		},
+		{
+			name:  "edge arming needs the hysteresis too",
+			rule:  Rule{ID: "r", Condition: "rsi_14 < 30", Trigger: TriggerEdge, Hysteresis: 5},
+			rsi:   []float64{32, 25, 40, 25},
+			fires: []bool{false, false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngine()
+			if err := e.Load(&RuleSet{Rules: []Rule{tt.rule}}); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			for i, rsi := range tt.rsi {
+				got := len(e.Evaluate(rsiSnapshot(rsi))) > 0
+				if got != tt.fires[i] {
+					t.Errorf("evaluation %d (rsi %.0f): fired = %v, want %v", i, rsi, got, tt.fires[i])
+				}
+				if got {
+					e.Ack(tt.rule.ID, "AAPL")
+				}
+			}
+		})
+	}
+}
+
+func TestEngineReloadKeepsEdgeState(t *testing.T) {
+	rule := Rule{ID: "r", Condition: "rsi_14 < 30", Trigger: TriggerEdge}
+	e := NewEngine()
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	e.Evaluate(rsiSnapshot(40))
+	if len(e.Evaluate(rsiSnapshot(25))) != 1 {
+		t.Fatal("edge rule did not fire on the transition to true")
+	}
+	e.Ack(rule.ID, "AAPL")
+
+	// Reloading the same condition keeps the rule disarmed
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Evaluate(rsiSnapshot(25))) != 0 {
+		t.Error("edge rule fired again after a reload")
+	}
+
+	// A changed condition starts over, disarmed
+	rule.Condition = "rsi_14 < 31"
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Evaluate(rsiSnapshot(25))) != 0 {
+		t.Error("edge rule with a changed condition fired without a false observation")
+	}
+}
+
+func TestEngineEdgeFiresUntilAcknowledged(t *testing.T) {
+	rule := Rule{ID: "r", Condition: "rsi_14 < 30", Trigger: TriggerEdge}
+	e := NewEngine()
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	e.Evaluate(rsiSnapshot(40))
+
+	// A fire the caller could not deliver, e.g. during a cooldown, is not
+	// acknowledged and comes back on the next evaluation
+	for i := 0; i < 3; i++ {
+		if len(e.Evaluate(rsiSnapshot(25))) != 1 {
+			t.Fatalf("evaluation %d: unacknowledged edge rule stopped firing", i)
+		}
+	}
+
+	e.Ack(rule.ID, "AAPL")
+	if len(e.Evaluate(rsiSnapshot(20))) != 0 {
+		t.Error("edge rule fired again after it was acknowledged")
+	}
+	e.Ack("missing", "AAPL")
+}
+
+func TestEngineReloadResetsChangedTrigger(t *testing.T) {
+	rule := Rule{ID: "r", Condition: "rsi_14 < 30", Trigger: TriggerEdge}
+	e := NewEngine()
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	e.Evaluate(rsiSnapshot(40))
+
+	// Armed state built for one hysteresis does not carry over to another
+	rule.Hysteresis = 5
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Evaluate(rsiSnapshot(25))) != 0 {
+		t.Error("edge rule fired with state from before its hysteresis changed")
+	}
+
+	// Nor across a switch to level and back
+	e.Evaluate(rsiSnapshot(40))
+	rule.Trigger = TriggerLevel
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	rule.Trigger = TriggerEdge
+	if err := e.Load(&RuleSet{Rules: []Rule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Evaluate(rsiSnapshot(20))) != 0 {
+		t.Error("edge rule fired with state kept from before its trigger changed")
+	}
+}
+
+func TestEngineSkipsUnknownValues(t *testing.T) {
+	e := NewEngine()
+	if err := e.Load(&RuleSet{Rules: []Rule{{ID: "r", Condition: "sma_200 > 0"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Evaluate(rsiSnapshot(25)); len(got) != 0 {
+		t.Errorf("rule on a missing indicator fired: %+v", got)
+	}
+}
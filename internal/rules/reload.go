@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Reloader keeps an engine in sync with a rule source without a restart.
+// It reloads on every tick of the poll interval and whenever the changes
+// channel signals, e.g. from a database LISTEN/NOTIFY subscription.
+type Reloader struct {
+	source   Source
+	engine   *Engine
+	interval time.Duration
+	changes  <-chan struct{}
+	lastHash [sha256.Size]byte
+}
+
+// NewReloader creates a reloader. A non-positive interval disables polling
+// and changes may be nil when the source has no change notifications.
+func NewReloader(source Source, engine *Engine, interval time.Duration, changes <-chan struct{}) *Reloader {
+	return &Reloader{
+		source:   source,
+		engine:   engine,
+		interval: interval,
+		changes:  changes,
+	}
+}
+
+// Reload loads the rule set from the source and installs it if it changed.
+// Validation errors for individual rules are logged; the valid rules are
+// still installed.
+func (r *Reloader) Reload(ctx context.Context) error {
+	set, err := r.source.LoadRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(raw)
+	if hash == r.lastHash {
+		return nil
+	}
+	r.lastHash = hash
+
+	if err := r.engine.Load(set); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	log.Printf("Loaded %d custom rule(s)", len(r.engine.Rules()))
+	return nil
+}
+
+// Run reloads rules until the context is cancelled
+func (r *Reloader) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if r.interval > 0 {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case _, ok := <-r.changes:
+			if !ok {
+				r.changes = nil
+				continue
+			}
+			log.Println("Rule change notification received, reloading rules")
+		}
+
+		if err := r.Reload(ctx); err != nil {
+			log.Printf("Failed to reload rules: %v", err)
+		}
+	}
+}
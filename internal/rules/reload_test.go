@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/market"
+)
+
+// fakeSource serves a rule set that tests can change, counting loads
+type fakeSource struct {
+	mu    sync.Mutex
+	set   *RuleSet
+	loads int
+}
+
+func (f *fakeSource) LoadRules(ctx context.Context) (*RuleSet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loads++
+	return f.set, nil
+}
+
+func (f *fakeSource) setRules(rules ...Rule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.set = &RuleSet{Rules: rules}
+}
+
+func (f *fakeSource) loadCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.loads
+}
+
+func TestReloaderReloadsOnChange(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{}
+	source.setRules(Rule{ID: "r", Condition: "rsi_14 < 30", Trigger: TriggerEdge})
+	engine := NewEngine()
+	r := NewReloader(source, engine, 0, nil)
+
+	if err := r.Reload(ctx); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if len(engine.Rules()) != 1 {
+		t.Fatalf("engine has %d rules after the first reload, want 1", len(engine.Rules()))
+	}
+	snap := market.Snapshot{Symbol: "AAPL", Price: 100, QuoteUpdatedAt: time.Now(), Indicators: map[string]float64{"rsi_14": 40}}
+	engine.Evaluate(snap)
+	snap.Indicators = map[string]float64{"rsi_14": 25}
+	if len(engine.Evaluate(snap)) != 1 {
+		t.Fatal("edge rule did not fire")
+	}
+
+	// An unchanged rule set is not reinstalled
+	engine.Load(&RuleSet{})
+	if err := r.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(engine.Rules()) != 0 {
+		t.Error("unchanged rule set was reinstalled")
+	}
+
+	// A changed rule set is
+	source.setRules(Rule{ID: "r", Condition: "rsi_14 < 30"}, Rule{ID: "s", Condition: "price > 50"})
+	if err := r.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(engine.Rules()) != 2 {
+		t.Errorf("engine has %d rules after a change, want 2", len(engine.Rules()))
+	}
+
+	// Invalid rules are skipped, the valid ones still installed
+	source.setRules(Rule{ID: "r", Condition: "rsi_14 <"}, Rule{ID: "s", Condition: "price > 50"})
+	if err := r.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := engine.Rules(); len(got) != 1 || got[0].ID != "s" {
+		t.Errorf("engine rules after an invalid change = %+v, want only s", got)
+	}
+}
+
+func TestReloaderRunOnNotification(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := &fakeSource{}
+	source.setRules(Rule{ID: "r", Condition: "rsi_14 < 30"})
+	changes := make(chan struct{})
+	r := NewReloader(source, NewEngine(), 0, changes)
+
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	changes <- struct{}{}
+	changes <- struct{}{}
+	// The second send only completes once the first reload has started
+	deadline := time.Now().Add(time.Second)
+	for source.loadCount() < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if source.loadCount() < 1 {
+		t.Error("no reload after a change notification")
+	}
+
+	// A closed channel stops notifications but not the loop
+	close(changes)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
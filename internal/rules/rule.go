@@ -0,0 +1,94 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Trigger modes
+const (
+	// TriggerLevel fires on every evaluation while the condition holds,
+	// subject to the rule's cooldown
+	TriggerLevel = "level"
+	// TriggerEdge fires once when the condition becomes true and re-arms
+	// only after it has been false by at least the rule's hysteresis
+	TriggerEdge = "edge"
+)
+
+// Rule is a custom alert condition evaluated against market state
+type Rule struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Type            string             `json:"type,omitempty"`      // EXPRESSION (default) or a fixed rule type
+	Params          map[string]float64 `json:"params,omitempty"`    // parameters for fixed rule types
+	Symbols         []string           `json:"symbols,omitempty"`   // empty with no group means every symbol
+	Group           string             `json:"group,omitempty"`     // named symbol group from the rule set
+	Condition       string             `json:"condition,omitempty"` // e.g. "price > sma_200 && rsi_14 < 35"
+	Message         string             `json:"message,omitempty"`   // optional action text shown in the alert
+	CooldownMinutes int                `json:"cooldown_minutes,omitempty"`
+	Trigger         string             `json:"trigger,omitempty"`    // level (default) or edge
+	Hysteresis      float64            `json:"hysteresis,omitempty"` // margin the condition must be false by to re-arm an edge rule
+	Channels        []string           `json:"channels,omitempty"`   // notification channels; empty means the default channel
+	Enabled         *bool              `json:"enabled,omitempty"`    // nil means enabled
+}
+
+// DisplayName returns the rule name, falling back to its ID
+func (r Rule) DisplayName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.ID
+}
+
+// IsEnabled reports whether the rule should be evaluated
+func (r Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// TriggerMode returns the rule's trigger mode, defaulting to level
+func (r Rule) TriggerMode() string {
+	if r.Trigger == "" {
+		return TriggerLevel
+	}
+	return r.Trigger
+}
+
+// RuleSet is a complete set of rules and the symbol groups they reference.
+// It is also the on-disk format of a rules file.
+type RuleSet struct {
+	Groups     map[string][]string `json:"groups,omitempty"`
+	Indicators []string            `json:"indicators,omitempty"` // extra indicator names accepted in conditions
+	Rules      []Rule              `json:"rules"`
+}
+
+// LoadFile reads a JSON rule set from disk
+func LoadFile(path string) (*RuleSet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var set RuleSet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+	return &set, nil
+}
+
+// Source provides the current rule set, e.g. from a file or a database
+type Source interface {
+	LoadRules(ctx context.Context) (*RuleSet, error)
+}
+
+// FileSource loads rules from a JSON file on every call, so edits to the
+// file are picked up on the next reload
+type FileSource struct {
+	Path string
+}
+
+// LoadRules implements Source
+func (f FileSource) LoadRules(ctx context.Context) (*RuleSet, error) {
+	return LoadFile(f.Path)
+}
@@ -0,0 +1,62 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/trogers1052/alert-service/internal/expr"
+	"github.com/trogers1052/alert-service/internal/market"
+)
+
+// quoteFields are the identifiers resolved from the latest quote
+var quoteFields = map[string]bool{
+	"price":  true,
+	"volume": true,
+	"bid":    true,
+	"ask":    true,
+	"spread": true,
+}
+
+// indicatorPattern matches the indicator names published on stock.indicators
+// and in decision IndicatorsSnapshot maps, with an optional period suffix
+// (e.g. rsi_14, sma_200, avg_volume_20)
+var indicatorPattern = regexp.MustCompile(`^(sma|ema|wma|rsi|atr|adx|cci|mfi|roc|obv|vwap|macd|macd_signal|macd_hist|macd_histogram|bb_upper|bb_middle|bb_lower|bb_width|stoch_k|stoch_d|avg_volume|volume_sma|open|high|low|close|prev_close|week52_high|week52_low|support|resistance)(_\d+)?$`)
+
+// Schema is the set of identifiers a rule condition may reference
+type Schema struct {
+	extra map[string]bool
+}
+
+// NewSchema creates a schema accepting quote fields, the standard indicator
+// families and any additional indicator names given
+func NewSchema(extraIndicators []string) *Schema {
+	s := &Schema{extra: make(map[string]bool)}
+	for _, name := range extraIndicators {
+		s.extra[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return s
+}
+
+// Known implements expr.Schema
+func (s *Schema) Known(name string) bool {
+	return quoteFields[name] || s.extra[name] || indicatorPattern.MatchString(name)
+}
+
+// SnapshotVars exposes a market snapshot to the expression evaluator
+func SnapshotVars(snap market.Snapshot) expr.Vars {
+	return expr.VarsFunc(func(name string) (float64, bool) {
+		switch name {
+		case "price":
+			return snap.Price, snap.HasQuote()
+		case "volume":
+			return snap.Volume, snap.HasQuote()
+		case "bid":
+			return snap.Bid, snap.Bid > 0
+		case "ask":
+			return snap.Ask, snap.Ask > 0
+		case "spread":
+			return snap.Ask - snap.Bid, snap.Bid > 0 && snap.Ask > 0
+		}
+		return snap.Indicator(name)
+	})
+}
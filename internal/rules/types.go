@@ -0,0 +1,100 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule types. Every fixed type is translated into an expression condition
+// from its parameters, so all rules share one evaluator.
+const (
+	TypeExpression      = "EXPRESSION"       // condition given verbatim
+	TypePriceTarget     = "PRICE_TARGET"     // params: above or below
+	TypeRSIOversold     = "RSI_OVERSOLD"     // params: threshold (30), period (14)
+	TypeRSIOverbought   = "RSI_OVERBOUGHT"   // params: threshold (70), period (14)
+	TypeSupportBounce   = "SUPPORT_BOUNCE"   // params: low, high, rsi (30), period (14)
+	TypeResistanceBreak = "RESISTANCE_BREAK" // params: level
+	TypeVolumeSpike     = "VOLUME_SPIKE"     // params: multiplier (2), period (20)
+)
+
+// RuleType returns the rule's type, defaulting to an expression rule
+func (r Rule) RuleType() string {
+	if r.Type == "" {
+		return TypeExpression
+	}
+	return strings.ToUpper(r.Type)
+}
+
+// Expression returns the condition evaluated for the rule, deriving it
+// from the parameters for fixed rule types
+func (r Rule) Expression() (string, error) {
+	p := params(r.Params)
+
+	switch r.RuleType() {
+	case TypeExpression:
+		if strings.TrimSpace(r.Condition) == "" {
+			return "", fmt.Errorf("condition is required for %s rules", TypeExpression)
+		}
+		return r.Condition, nil
+
+	case TypePriceTarget:
+		above, hasAbove := r.Params["above"]
+		below, hasBelow := r.Params["below"]
+		switch {
+		case hasAbove && hasBelow:
+			return "", fmt.Errorf("%s takes either above or below, not both", TypePriceTarget)
+		case hasAbove:
+			return fmt.Sprintf("price >= %s", num(above)), nil
+		case hasBelow:
+			return fmt.Sprintf("price <= %s", num(below)), nil
+		}
+		return "", fmt.Errorf("%s requires an above or below parameter", TypePriceTarget)
+
+	case TypeRSIOversold:
+		return fmt.Sprintf("rsi_%d < %s", p.period(14), num(p.get("threshold", 30))), nil
+
+	case TypeRSIOverbought:
+		return fmt.Sprintf("rsi_%d > %s", p.period(14), num(p.get("threshold", 70))), nil
+
+	case TypeSupportBounce:
+		low, hasLow := r.Params["low"]
+		high, hasHigh := r.Params["high"]
+		if !hasLow || !hasHigh {
+			return "", fmt.Errorf("%s requires low and high parameters", TypeSupportBounce)
+		}
+		return fmt.Sprintf("price >= %s && price <= %s && rsi_%d < %s",
+			num(low), num(high), p.period(14), num(p.get("rsi", 30))), nil
+
+	case TypeResistanceBreak:
+		level, ok := r.Params["level"]
+		if !ok {
+			return "", fmt.Errorf("%s requires a level parameter", TypeResistanceBreak)
+		}
+		return fmt.Sprintf("crosses_above(price, %s)", num(level)), nil
+
+	case TypeVolumeSpike:
+		return fmt.Sprintf("volume > %s * avg_volume_%d", num(p.get("multiplier", 2)), p.period(20)), nil
+	}
+
+	return "", fmt.Errorf("unknown rule type %q", r.Type)
+}
+
+type params map[string]float64
+
+func (p params) get(name string, def float64) float64 {
+	if v, ok := p[name]; ok {
+		return v
+	}
+	return def
+}
+
+func (p params) period(def int) int {
+	if v, ok := p["period"]; ok && v >= 1 {
+		return int(v)
+	}
+	return def
+}
+
+func num(v float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.6f", v), "0"), ".")
+}
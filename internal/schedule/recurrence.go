@@ -0,0 +1,121 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Recurrence is a repeating delivery time, either a fixed interval or a
+// list of clock times on some days of the week
+type Recurrence struct {
+	spec     string
+	interval time.Duration
+	times    []int   // minutes after midnight, sorted
+	days     [7]bool // indexed by time.Weekday
+	loc      *time.Location
+}
+
+// ParseRecurrence parses "every 60m" or "at 09:00,12:30,16:15". Clock
+// times may be limited to some days, as in "fri at 17:00" or
+// "mon-fri at 16:30". Clock times are in loc; a nil loc means UTC.
+func ParseRecurrence(spec string, loc *time.Location) (*Recurrence, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	r := &Recurrence{spec: strings.TrimSpace(spec), loc: loc}
+
+	kind, value, _ := strings.Cut(r.spec, " ")
+	if days, err := parseDays(kind); err == nil {
+		for _, day := range days {
+			r.days[day] = true
+		}
+		kind, value, _ = strings.Cut(strings.TrimSpace(value), " ")
+		if !strings.EqualFold(kind, "at") {
+			return nil, fmt.Errorf(`recurrence %q: want days followed by "at HH:MM,..."`, spec)
+		}
+	} else {
+		r.days = [7]bool{true, true, true, true, true, true, true}
+	}
+	value = strings.ReplaceAll(value, " ", "")
+	switch strings.ToLower(kind) {
+	case "every":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < time.Minute {
+			return nil, fmt.Errorf("recurrence %q: want a duration of at least 1m", spec)
+		}
+		r.interval = d
+
+	case "at":
+		for _, clock := range strings.Split(value, ",") {
+			minute, err := parseClock(clock)
+			if err != nil || minute >= minutesPerDay {
+				return nil, fmt.Errorf("recurrence %q: invalid time %q", spec, clock)
+			}
+			r.times = append(r.times, minute)
+		}
+		sort.Ints(r.times)
+
+	default:
+		return nil, fmt.Errorf(`recurrence %q: want "every <duration>" or "at HH:MM,..."`, spec)
+	}
+	return r, nil
+}
+
+// ParseRecurrences parses per-name recurrences such as
+// "email=every 60m;telegram=at 09:00,16:30". Names are lower-cased.
+func ParseRecurrences(spec string, loc *time.Location) (map[string]*Recurrence, error) {
+	out := make(map[string]*Recurrence)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q: want name=recurrence", entry)
+		}
+		r, err := ParseRecurrence(value, loc)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = r
+	}
+	return out, nil
+}
+
+// Next returns the first occurrence strictly after t
+func (r *Recurrence) Next(t time.Time) time.Time {
+	if r.interval > 0 {
+		return t.Truncate(r.interval).Add(r.interval)
+	}
+
+	local := t.In(r.loc)
+	for day := 0; day <= 7; day++ {
+		date := local.AddDate(0, 0, day)
+		if !r.days[date.Weekday()] {
+			continue
+		}
+		y, m, d := date.Date()
+		for _, minute := range r.times {
+			next := time.Date(y, m, d, minute/60, minute%60, 0, 0, r.loc)
+			if next.After(t) {
+				return next
+			}
+		}
+	}
+	// Unreachable with at least one day and time
+	return t.Add(7 * 24 * time.Hour)
+}
+
+// Location returns the zone clock times are evaluated in
+func (r *Recurrence) Location() *time.Location {
+	return r.loc
+}
+
+// String returns the recurrence spec
+func (r *Recurrence) String() string {
+	return r.spec
+}
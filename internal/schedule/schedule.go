@@ -0,0 +1,240 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/calendar"
+)
+
+const minutesPerDay = 24 * 60
+
+// window is a quiet period in minutes from the start of its day. end may
+// exceed minutesPerDay when the window runs past midnight.
+type window struct {
+	start, end int
+}
+
+// Schedule is a parsed weekly quiet-hour schedule in a time zone
+type Schedule struct {
+	spec     string
+	loc      *time.Location
+	days     [7][]window // indexed by time.Weekday
+	sessions map[calendar.Session]bool
+}
+
+// Parse parses a schedule spec evaluated in loc; a nil loc means UTC.
+//
+// A spec is a list of clauses separated by semicolons, each naming its days
+// followed by HH:MM-HH:MM windows or "all" for the whole day:
+//
+//	mon-fri 22:00-07:00; sat,sun all
+//	daily 12:00-13:00,22:30-06:45
+//
+// Days are mon..sun, ranges like mon-fri, or daily, weekdays and weekends.
+// A window ending at or before its start runs past midnight and belongs to
+// the day it starts on, so "fri 22:00-07:00" also covers Saturday morning.
+//
+// A "session" clause makes US market sessions quiet, independent of loc:
+//
+//	session closed,afterhours
+func Parse(spec string, loc *time.Location) (*Schedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	s := &Schedule{spec: strings.TrimSpace(spec), loc: loc}
+
+	for _, clause := range strings.Split(spec, ";") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		fields := strings.Fields(clause)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("quiet hours %q: want days followed by windows", clause)
+		}
+
+		if strings.EqualFold(fields[0], "session") {
+			if err := s.addSessions(strings.Join(fields[1:], "")); err != nil {
+				return nil, fmt.Errorf("quiet hours %q: %w", clause, err)
+			}
+			continue
+		}
+
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours %q: %w", clause, err)
+		}
+		windows, err := parseWindows(strings.Join(fields[1:], ""))
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours %q: %w", clause, err)
+		}
+
+		for _, day := range days {
+			s.days[day] = append(s.days[day], windows...)
+		}
+	}
+	return s, nil
+}
+
+// LoadLocation resolves an IANA time zone name, treating an empty name as UTC
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// Quiet reports whether t falls inside a quiet window
+func (s *Schedule) Quiet(t time.Time) bool {
+	if s == nil {
+		return false
+	}
+
+	if len(s.sessions) > 0 && s.sessions[calendar.NYSE().Session(t)] {
+		return true
+	}
+
+	local := t.In(s.loc)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+
+	for _, w := range s.days[today] {
+		if minute >= w.start && minute < w.end {
+			return true
+		}
+	}
+	// Windows from the previous day that run past midnight
+	for _, w := range s.days[yesterday] {
+		if w.end > minutesPerDay && minute < w.end-minutesPerDay {
+			return true
+		}
+	}
+	return false
+}
+
+// Location returns the schedule's time zone
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// String returns the schedule spec and zone
+func (s *Schedule) String() string {
+	return fmt.Sprintf("%s (%s)", s.spec, s.loc)
+}
+
+func (s *Schedule) addSessions(field string) error {
+	if s.sessions == nil {
+		s.sessions = make(map[calendar.Session]bool)
+	}
+	for _, name := range strings.Split(strings.ToLower(field), ",") {
+		switch session := calendar.Session(name); session {
+		case calendar.PreMarket, calendar.Regular, calendar.AfterHours, calendar.Closed:
+			s.sessions[session] = true
+		default:
+			return fmt.Errorf("unknown session %q", name)
+		}
+	}
+	return nil
+}
+
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func parseDays(field string) ([]time.Weekday, error) {
+	switch strings.ToLower(field) {
+	case "daily":
+		return []time.Weekday{0, 1, 2, 3, 4, 5, 6}, nil
+	case "weekdays":
+		return []time.Weekday{1, 2, 3, 4, 5}, nil
+	case "weekends":
+		return []time.Weekday{time.Saturday, time.Sunday}, nil
+	}
+
+	var days []time.Weekday
+	for _, part := range strings.Split(strings.ToLower(field), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		start, ok := dayNames[from]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", from)
+		}
+		if !isRange {
+			days = append(days, start)
+			continue
+		}
+		end, ok := dayNames[to]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", to)
+		}
+		// Ranges may wrap the week, e.g. fri-mon
+		for d := start; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == end {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseWindows(field string) ([]window, error) {
+	if strings.EqualFold(field, "all") {
+		return []window{{start: 0, end: minutesPerDay}}, nil
+	}
+
+	var windows []window
+	for _, part := range strings.Split(field, ",") {
+		from, to, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("window %q: want HH:MM-HH:MM", part)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		if end <= start {
+			end += minutesPerDay
+		}
+		windows = append(windows, window{start: start, end: end})
+	}
+	return windows, nil
+}
+
+// parseClock parses HH:MM into minutes after midnight; 24:00 is allowed as an end
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("time %q: want HH:MM", s)
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil {
+		return 0, fmt.Errorf("time %q: want HH:MM", s)
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil {
+		return 0, fmt.Errorf("time %q: want HH:MM", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("time %q out of range", s)
+	}
+	return h*60 + m, nil
+}
@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleQuiet(t *testing.T) {
+	ny, err := LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2026-03-02 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		loc   *time.Location
+		at    time.Time
+		quiet bool
+	}{
+		{"empty spec", "", nil, at(2, 3, 0), false},
+		{"inside a window", "daily 12:00-13:00", nil, at(2, 12, 30), true},
+		{"window end is exclusive", "daily 12:00-13:00", nil, at(2, 13, 0), false},
+		{"overnight before midnight", "mon-fri 22:00-07:00", nil, at(2, 23, 0), true},
+		{"overnight after midnight", "mon-fri 22:00-07:00", nil, at(3, 6, 59), true},
+		{"overnight belongs to its start day", "mon-fri 22:00-07:00", nil, at(2, 6, 0), false},
+		{"friday night runs into saturday", "fri 22:00-07:00", nil, at(7, 3, 0), true},
+		{"all day", "sat,sun all", nil, at(8, 12, 0), true},
+		{"weekday outside weekend clause", "weekends all", nil, at(6, 12, 0), false},
+		{"wrapping day range", "fri-mon all", nil, at(2, 12, 0), true},
+		{"second clause", "mon 01:00-02:00; tue 12:00-13:00", nil, at(3, 12, 15), true},
+		{"evaluated in the zone", "daily 22:00-07:00", ny, at(3, 4, 0), true},
+		{"zone shifts the window", "daily 22:00-07:00", ny, at(2, 23, 0), false},
+		{"closed session", "session closed", nil, at(7, 15, 0), true},
+		{"regular session not listed", "session closed,afterhours", nil, at(2, 15, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec, tt.loc)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			if got := s.Quiet(tt.at); got != tt.quiet {
+				t.Errorf("Quiet(%s) = %v, want %v", tt.at, got, tt.quiet)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"mon",
+		"funday 10:00-11:00",
+		"mon-funday all",
+		"mon 10:00",
+		"mon 25:00-26:00",
+		"mon 10:60-11:00",
+		"mon 24:30-01:00",
+		"mon ten-eleven",
+		"session lunch",
+	} {
+		if _, err := Parse(spec, nil); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	// 2026-03-06 is a Friday
+	from := time.Date(2026, 3, 6, 16, 45, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"every 60m", time.Date(2026, 3, 6, 17, 0, 0, 0, time.UTC)},
+		{"at 09:00,16:30", time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)},
+		{"at 16:30,17:00", time.Date(2026, 3, 6, 17, 0, 0, 0, time.UTC)},
+		{"mon-fri at 16:30", time.Date(2026, 3, 9, 16, 30, 0, 0, time.UTC)},
+		{"fri at 16:45", time.Date(2026, 3, 13, 16, 45, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.spec, nil)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q): %v", tt.spec, err)
+		}
+		if got := r.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.spec, from, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"every 30s", "at 24:00", "daily 09:00", "sometimes"} {
+		if _, err := ParseRecurrence(spec, nil); err == nil {
+			t.Errorf("ParseRecurrence(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -8,26 +8,88 @@ import (
 	"sync"
 	"time"
 
+	"github.com/trogers1052/alert-service/internal/calendar"
+	"github.com/trogers1052/alert-service/internal/classify"
 	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/cooldown"
+	"github.com/trogers1052/alert-service/internal/market"
 	"github.com/trogers1052/alert-service/internal/models"
-	"github.com/trogers1052/alert-service/internal/telegram"
+	"github.com/trogers1052/alert-service/internal/notify"
+	"github.com/trogers1052/alert-service/internal/portfolio"
+	"github.com/trogers1052/alert-service/internal/rules"
+	"github.com/trogers1052/alert-service/internal/schedule"
+	"github.com/trogers1052/alert-service/internal/storage"
+	"github.com/trogers1052/alert-service/internal/trend"
+	"github.com/trogers1052/alert-service/internal/watchlist"
 )
 
 // AlertService handles alert logic and message formatting
 type AlertService struct {
-	config         *config.Config
-	telegramClient *telegram.Client
-	cooldowns      map[string]time.Time // symbol -> last alert time
-	cooldownMu     sync.RWMutex
+	config     *config.Config
+	notifier   *notify.Dispatcher
+	market     *market.State
+	rules      *rules.Engine
+	store      storage.Store // nil disables persistence
+	policy     cooldown.Policy
+	calendar   *calendar.Calendar
+	sessions   calendar.Filter // market sessions each alert kind is limited to
+	quiet      *quietHours
+	queue      storage.QueueStore              // alerts held for the quiet hours and channel digests
+	digests    map[string]*schedule.Recurrence // channel -> digest schedule
+	reports    []report                        // scheduled history reports
+	outcomes   *outcomeTracker                 // nil unless outcomes are tracked
+	rankings   rankingCache                    // latest ranking per signal type
+	watchlists *watchlist.Set                  // named symbol lists for filtering, routing and cooldowns
+	routes     map[string][]string             // watchlist -> channels its symbols' decisions go to
+	positions  *portfolio.Book                 // nil unless positions are configured
+	classifier *classify.Classifier            // tags decisions, e.g. scale-in
+	trends     *trend.Tracker                  // nil unless confidence trends are tracked
+	cooldowns  map[string]storage.Cooldown     // cooldown key -> last alert
+	cooldownMu sync.RWMutex
+	signals    map[string]lastSignal // symbol -> last alerted BUY or SELL, for flip detection
+	signalsMu  sync.Mutex
 }
 
-// NewAlertService creates a new alert service
-func NewAlertService(cfg *config.Config, telegramClient *telegram.Client) *AlertService {
-	return &AlertService{
-		config:         cfg,
-		telegramClient: telegramClient,
-		cooldowns:      make(map[string]time.Time),
+// NewAlertService creates a new alert service. ruleEngine and store may be nil.
+// Persisted cooldowns and pending signal outcomes are loaded so a restart
+// does not reset them.
+func NewAlertService(cfg *config.Config, notifier *notify.Dispatcher, marketState *market.State, ruleEngine *rules.Engine, store storage.Store) *AlertService {
+	watchlists := newWatchlists(cfg, store)
+	s := &AlertService{
+		config:     cfg,
+		notifier:   notifier,
+		market:     marketState,
+		rules:      ruleEngine,
+		store:      store,
+		policy:     newCooldownPolicy(cfg, watchlists),
+		calendar:   calendar.NYSE(),
+		sessions:   newSessionFilter(cfg),
+		quiet:      newQuietHours(cfg, store),
+		queue:      &memoryQueue{},
+		digests:    newChannelDigests(cfg),
+		reports:    newReports(cfg),
+		watchlists: watchlists,
+		routes:     newWatchlistRoutes(cfg),
+		classifier: classify.Default(),
+		trends:     newTrendTracker(cfg),
+		cooldowns:  make(map[string]storage.Cooldown),
+		signals:    make(map[string]lastSignal),
 	}
+	if store != nil {
+		s.queue = store
+		if cfg.TrackOutcomes {
+			s.outcomes = &outcomeTracker{pending: make(map[string][]*storage.Outcome)}
+		}
+	} else if (cfg.QuietHoursDigest && cfg.QuietHours != "") || len(s.digests) > 0 {
+		log.Printf("Warning: no storage backend, alerts held for digests are kept in memory and lost on restart; set STORAGE_BACKEND to keep them")
+	}
+	s.checkDigestChannels()
+	s.checkWatchlistChannels()
+	s.loadCooldowns()
+	s.loadOutcomes()
+	s.loadRankings()
+	s.loadTrends()
+	return s
 }
 
 // HandleDecisionEvent processes a decision event and sends alerts if appropriate
@@ -39,9 +101,48 @@ func (s *AlertService) HandleDecisionEvent(ctx context.Context, event interface{
 
 	data := decision.Data
 
+	// Decision snapshots carry indicators that custom rules may reference
+	if len(data.IndicatorsSnapshot) > 0 {
+		s.market.UpdateIndicators(models.IndicatorData{
+			Symbol:     data.Symbol,
+			Timestamp:  decision.Timestamp,
+			Indicators: data.IndicatorsSnapshot,
+		})
+	}
+
+	// Every decision counts towards the confidence trend, alerted or not
+	points := s.trackConfidence(decision)
+
+	// Render up front so suppressed alerts are recorded with the text they would have had.
+	// A BUY or SELL reversing the symbol's last alerted signal gets its own message.
+	message := s.formatDecisionMessage(decision)
+	flip := s.detectFlip(&data)
+	if flip != nil {
+		message = s.formatFlipMessage(decision, flip)
+	}
+	record := &storage.AlertRecord{
+		Kind:       storage.KindDecision,
+		Symbol:     data.Symbol,
+		Signal:     data.Signal,
+		Confidence: data.Confidence,
+		ScaleIn:    data.Signal == models.SignalBuy && s.isScaleInSignal(&data),
+		Message:    message,
+		EventTime:  decision.Timestamp,
+	}
+	// Measure forward returns once the record is stored, alerted or not
+	defer s.trackOutcome(ctx, decision, record)
+
 	// Check if we should alert for this signal type
 	if !s.shouldAlertForSignal(data.Signal) {
 		log.Printf("Skipping alert for %s %s signal (not configured)", data.Symbol, data.Signal)
+		s.suppress(ctx, record, storage.ReasonSignalDisabled)
+		return nil
+	}
+
+	// Check market session restrictions for this signal
+	if !s.inAllowedSession(data.Signal, decision.Timestamp) {
+		log.Printf("Skipping alert for %s %s signal: outside allowed market sessions", data.Symbol, data.Signal)
+		s.suppress(ctx, record, storage.ReasonOutsideSession)
 		return nil
 	}
 
@@ -49,29 +150,117 @@ func (s *AlertService) HandleDecisionEvent(ctx context.Context, event interface{
 	if data.Confidence < s.config.MinConfidence {
 		log.Printf("Skipping alert for %s: confidence %.2f below threshold %.2f",
 			data.Symbol, data.Confidence, s.config.MinConfidence)
+		s.suppress(ctx, record, storage.ReasonBelowConfidence)
 		return nil
 	}
 
-	// Check cooldown
-	if !s.checkCooldown(data.Symbol) {
-		log.Printf("Skipping alert for %s: in cooldown period", data.Symbol)
+	// Check the triggered rules against the rule filters
+	if reason, why := s.checkDecisionRules(&data); reason != "" {
+		log.Printf("Skipping alert for %s %s: %s", data.Symbol, data.Signal, why)
+		s.suppress(ctx, record, reason)
 		return nil
 	}
 
-	// Check quiet hours
-	if s.isQuietHours() {
-		log.Printf("Skipping alert for %s: quiet hours active", data.Symbol)
+	// Only SELL what is held
+	if !s.isHeldForSell(&data) {
+		log.Printf("Skipping alert for %s SELL: no open position", data.Symbol)
+		s.suppress(ctx, record, storage.ReasonNotHeld)
 		return nil
 	}
 
-	// Format and send the message
-	message := s.formatDecisionMessage(decision)
-	if err := s.telegramClient.SendMessage(ctx, message); err != nil {
-		return fmt.Errorf("failed to send telegram message: %w", err)
+	// Check ranking membership
+	if !s.inRankingTopN(&data) {
+		log.Printf("Skipping alert for %s %s: not in the top %d %s rankings",
+			data.Symbol, data.Signal, s.config.RankingFilterTopN, data.Signal)
+		s.suppress(ctx, record, storage.ReasonNotRanked)
+		return nil
+	}
+
+	// Tags can raise or lower the priority and pick the channels
+	tags := s.decisionTags(&data)
+	priority := classify.Priority(tags)
+
+	// Check watchlists
+	lowPriority := priority == classify.PriorityLow
+	if s.isUnlisted(data.Symbol) {
+		switch s.config.WatchlistUnlisted {
+		case unlistedDrop:
+			log.Printf("Skipping alert for %s: not on an active watchlist", data.Symbol)
+			s.suppress(ctx, record, storage.ReasonNotWatched)
+			return nil
+		case unlistedRoute:
+			lowPriority = true
+		}
+	}
+
+	// Check mutes
+	if s.isMuted(ctx, data.Symbol) {
+		log.Printf("Skipping alert for %s: symbol muted", data.Symbol)
+		s.suppress(ctx, record, storage.ReasonMuted)
+		return nil
+	}
+
+	// Check cooldown; reversals and rising confidence are always reported
+	cooldownCheck := s.checkDecisionCooldown(&data)
+	momentum := s.confidenceMomentum(points)
+	switch {
+	case flip != nil:
+		log.Printf("Signal reversed for %s: %s -> %s", data.Symbol, flip.Signal, data.Signal)
+	case !cooldownCheck.Allowed && momentum != "":
+		log.Printf("Cooldown bypassed for %s %s: confidence %s", data.Symbol, data.Signal, momentum)
+	case !cooldownCheck.Allowed:
+		log.Printf("Skipping alert for %s %s: in cooldown period (%s left)",
+			data.Symbol, data.Signal, cooldownCheck.Remaining.Round(time.Second))
+		s.suppress(ctx, record, storage.ReasonCooldown)
+		return nil
+	}
+	if cooldownCheck.Reason == cooldown.ReasonDirectionChange || cooldownCheck.Reason == cooldown.ReasonConfidenceJump {
+		log.Printf("Cooldown bypassed for %s %s: %s", data.Symbol, data.Signal, cooldownCheck.Reason)
+	}
+
+	// Check quiet hours; urgent SELLs still reach every subscriber
+	breakthrough := s.breaksThroughQuietHours(&data) || priority == classify.PriorityHigh
+	if breakthrough {
+		ctx = withBreakthrough(ctx)
+	}
+
+	// Route to real-time channels; channels in digest mode get it in their next digest
+	realtime, batched := s.splitChannels(ctx, s.decisionChannels(data.Symbol, tags, lowPriority))
+	if !breakthrough {
+		if s.isQuietHours(ctx) {
+			log.Printf("Holding alert for %s: quiet hours active", data.Symbol)
+			s.holdForDigest(ctx, record, realtime, batched)
+			s.noteSignal(decision)
+			return nil
+		}
+		// Chats in their own quiet hours get it in their digest
+		s.holdForQuietChats(ctx, record, realtime)
+	}
+
+	if len(realtime) == 0 {
+		record.Queue(storage.ReasonDigest)
+		s.recordAlert(ctx, record)
+		s.batchForDigest(ctx, record, batched)
+		s.setDecisionCooldown(ctx, &data)
+		s.noteSignal(decision)
+		log.Printf("Batched alert for %s %s signal for digest", data.Symbol, data.Signal)
+		return nil
+	}
+
+	deliveries, err := s.notifier.Send(ctx, message, realtime)
+	record.SetDeliveries(deliveries)
+	s.recordAlert(ctx, record)
+	s.batchForDigest(ctx, record, batched)
+	if record.Status == storage.StatusFailed {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	if err != nil {
+		log.Printf("Alert for %s only partially delivered: %v", data.Symbol, err)
 	}
 
 	// Update cooldown
-	s.setCooldown(data.Symbol)
+	s.setDecisionCooldown(ctx, &data)
+	s.noteSignal(decision)
 
 	log.Printf("Sent alert for %s %s signal (confidence: %.2f)", data.Symbol, data.Signal, data.Confidence)
 	return nil
@@ -84,21 +273,57 @@ func (s *AlertService) HandleRankingEvent(ctx context.Context, event interface{}
 		return fmt.Errorf("invalid event type for ranking handler")
 	}
 
+	s.recordRanking(ctx, ranking)
+	trends := s.rankTrends(ctx, ranking)
+
+	// Show what changed since the previous ranking of this type, once there is one
+	message := s.formatRankingMessage(ranking, trends)
+	var diff *rankingDiff
+	if previous := s.rankings.swap(&ranking.Data); previous != nil && s.config.RankingsDiff {
+		diff = s.diffRankings(previous, &ranking.Data)
+		message = s.formatRankingDiff(ranking, previous, diff, trends)
+	}
+	record := &storage.AlertRecord{
+		Kind:      storage.KindRanking,
+		Signal:    ranking.Data.SignalType,
+		Message:   message,
+		EventTime: ranking.Timestamp,
+	}
+
 	// Check if ranking alerts are enabled
 	if !s.config.AlertOnRankings {
+		s.suppress(ctx, record, storage.ReasonRankingsOff)
 		return nil
 	}
 
-	// Check quiet hours
-	if s.isQuietHours() {
-		log.Printf("Skipping ranking alert: quiet hours active")
+	// Check market session restrictions for rankings
+	if !s.inAllowedSession(sessionKindRankings, ranking.Timestamp) {
+		log.Printf("Skipping ranking alert: outside allowed market sessions")
+		s.suppress(ctx, record, storage.ReasonOutsideSession)
+		return nil
+	}
+
+	// Skip rankings whose top N did not change
+	if diff != nil && diff.empty() && s.config.RankingsSkipUnchanged {
+		log.Printf("Skipping %s ranking alert: top %d unchanged", ranking.Data.SignalType, s.config.RankingsTopN)
+		s.suppress(ctx, record, storage.ReasonNoChange)
 		return nil
 	}
 
-	// Format and send the message
-	message := s.formatRankingMessage(ranking)
-	if err := s.telegramClient.SendMessage(ctx, message); err != nil {
-		return fmt.Errorf("failed to send telegram ranking message: %w", err)
+	// Check quiet hours
+	if s.isQuietHours(ctx) {
+		log.Printf("Holding ranking alert: quiet hours active")
+		s.holdForDigest(ctx, record, s.notifier.DefaultChannels(), nil)
+		return nil
+	}
+	s.holdForQuietChats(ctx, record, s.notifier.DefaultChannels())
+
+	// Send the message
+	deliveries, err := s.notifier.Send(ctx, message, nil)
+	record.SetDeliveries(deliveries)
+	s.recordAlert(ctx, record)
+	if record.Status == storage.StatusFailed {
+		return fmt.Errorf("failed to send ranking message: %w", err)
 	}
 
 	log.Printf("Sent ranking alert for %s signals (%d symbols)",
@@ -106,6 +331,39 @@ func (s *AlertService) HandleRankingEvent(ctx context.Context, event interface{}
 	return nil
 }
 
+// HandleQuoteEvent applies a real-time quote to the market state
+func (s *AlertService) HandleQuoteEvent(ctx context.Context, event interface{}) error {
+	quote, ok := event.(*models.QuoteEvent)
+	if !ok {
+		return fmt.Errorf("invalid event type for quote handler")
+	}
+
+	if quote.Data.Timestamp.IsZero() {
+		quote.Data.Timestamp = quote.Timestamp
+	}
+	if s.market.UpdateQuote(quote.Data) {
+		s.updateOutcomes(ctx, quote.Data)
+		return s.evaluateRules(ctx, quote.Data.Symbol)
+	}
+	return nil
+}
+
+// HandleIndicatorEvent applies indicator values to the market state
+func (s *AlertService) HandleIndicatorEvent(ctx context.Context, event interface{}) error {
+	indicators, ok := event.(*models.IndicatorEvent)
+	if !ok {
+		return fmt.Errorf("invalid event type for indicator handler")
+	}
+
+	if indicators.Data.Timestamp.IsZero() {
+		indicators.Data.Timestamp = indicators.Timestamp
+	}
+	if s.market.UpdateIndicators(indicators.Data) {
+		return s.evaluateRules(ctx, indicators.Data.Symbol)
+	}
+	return nil
+}
+
 // shouldAlertForSignal checks if alerts are enabled for a signal type
 func (s *AlertService) shouldAlertForSignal(signal string) bool {
 	switch signal {
@@ -120,67 +378,21 @@ func (s *AlertService) shouldAlertForSignal(signal string) bool {
 	}
 }
 
-// checkCooldown returns true if we can send an alert for this symbol
-func (s *AlertService) checkCooldown(symbol string) bool {
-	s.cooldownMu.RLock()
-	lastAlert, exists := s.cooldowns[symbol]
-	s.cooldownMu.RUnlock()
-
-	if !exists {
-		return true
-	}
-
-	cooldownDuration := time.Duration(s.config.CooldownMinutes) * time.Minute
-	return time.Since(lastAlert) >= cooldownDuration
-}
-
-// setCooldown updates the cooldown time for a symbol
-func (s *AlertService) setCooldown(symbol string) {
-	s.cooldownMu.Lock()
-	s.cooldowns[symbol] = time.Now()
-	s.cooldownMu.Unlock()
-}
-
-// isQuietHours checks if current time is within quiet hours
-func (s *AlertService) isQuietHours() bool {
-	if !s.config.EnableQuietHours {
-		return false
-	}
-
-	now := time.Now()
-	hour := now.Hour()
-
-	start := s.config.QuietHoursStart
-	end := s.config.QuietHoursEnd
-
-	// Handle overnight quiet hours (e.g., 22:00 to 07:00)
-	if start > end {
-		return hour >= start || hour < end
-	}
-
-	// Same-day quiet hours (e.g., 13:00 to 14:00)
-	return hour >= start && hour < end
-}
-
 // formatDecisionMessage formats a decision event into a Telegram message
 func (s *AlertService) formatDecisionMessage(event *models.DecisionEvent) string {
 	data := event.Data
 
-	// Check if this is a scale-in (average down) signal
-	isScaleIn := s.isScaleInSignal(data)
+	// Tags such as scale-in can relabel the signal
+	tags := s.decisionTags(&data)
+	isScaleIn := data.Signal == models.SignalBuy && classify.Has(tags, classify.ScaleIn)
 
 	// Signal emoji
 	var emoji string
 	var signalLabel string
 	switch data.Signal {
 	case models.SignalBuy:
-		if isScaleIn {
-			emoji = "📈"
-			signalLabel = "SCALE-IN"
-		} else {
-			emoji = "🟢"
-			signalLabel = "BUY"
-		}
+		emoji = "🟢"
+		signalLabel = "BUY"
 	case models.SignalSell:
 		emoji = "🔴"
 		signalLabel = "SELL"
@@ -188,6 +400,7 @@ func (s *AlertService) formatDecisionMessage(event *models.DecisionEvent) string
 		emoji = "👀"
 		signalLabel = "WATCH"
 	}
+	emoji, signalLabel = tagStyle(tags, emoji, signalLabel)
 
 	// Confidence bar
 	confidenceBar := s.formatConfidenceBar(data.Confidence)
@@ -197,13 +410,28 @@ func (s *AlertService) formatDecisionMessage(event *models.DecisionEvent) string
 	// Header - different format for scale-in
 	if isScaleIn {
 		sb.WriteString(fmt.Sprintf("%s <b>%s Signal: %s</b>\n", emoji, signalLabel, data.Symbol))
-		sb.WriteString("➕ <i>Adding to existing position</i>\n\n")
+		sb.WriteString("➕ <i>Adding to existing position</i>\n")
 	} else {
-		sb.WriteString(fmt.Sprintf("%s <b>%s Signal: %s</b>\n\n", emoji, signalLabel, data.Symbol))
+		sb.WriteString(fmt.Sprintf("%s <b>%s Signal: %s</b>\n", emoji, signalLabel, data.Symbol))
 	}
+	sb.WriteString(formatTagLine(tags))
+	sb.WriteString("\n")
 
 	// Confidence
-	sb.WriteString(fmt.Sprintf("📊 Confidence: %.0f%% %s\n\n", data.Confidence*100, confidenceBar))
+	sb.WriteString(fmt.Sprintf("📊 Confidence: %.0f%% %s\n", data.Confidence*100, confidenceBar))
+	sb.WriteString(s.formatTrendLine(&data))
+
+	// Current price from market state
+	if line := s.formatPriceLine(data.Symbol); line != "" {
+		sb.WriteString(line)
+	}
+
+	// Open position and its unrealized P&L
+	sb.WriteString(s.formatPositionLine(&data))
+
+	// Position in the latest ranking of the same signal type
+	sb.WriteString(s.formatRankingLine(&data))
+	sb.WriteString("\n")
 
 	// Primary reasoning
 	sb.WriteString(fmt.Sprintf("💡 <b>Reason:</b>\n%s\n\n", data.PrimaryReasoning))
@@ -226,41 +454,53 @@ func (s *AlertService) formatDecisionMessage(event *models.DecisionEvent) string
 		sb.WriteString("\n")
 	}
 
-	// Scale-in specific info
+	// Scale-in specific info; the generic advice gives way to a suggested size
+	size, sized := s.sizePosition(&data)
 	if isScaleIn {
-		sb.WriteString("⚠️ <b>Note:</b> This is an averaging down opportunity.\n")
-		sb.WriteString("Review your position size before adding.\n\n")
+		note := "This is an averaging down opportunity."
+		if position, held, _ := s.heldPosition(data.Symbol); held {
+			if price, ok := s.entryPrice(&data); ok && price > position.CostBasis {
+				note = "This adds above your average cost."
+			}
+		}
+		sb.WriteString(fmt.Sprintf("⚠️ <b>Note:</b> %s\n", note))
+		if !sized {
+			sb.WriteString("Review your position size before adding.\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Suggested share count and dollar risk for BUYs
+	if sized {
+		sb.WriteString(s.formatSizing(size, isScaleIn))
 	}
 
 	// Timestamp
-	sb.WriteString(fmt.Sprintf("🕐 %s", event.Timestamp.Format("2006-01-02 15:04:05 MST")))
+	sb.WriteString(fmt.Sprintf("🕐 %s · %s", event.Timestamp.Format("2006-01-02 15:04:05 MST"), s.sessionLabel(event.Timestamp)))
 
 	return sb.String()
 }
 
-// isScaleInSignal checks if this is an average down / scale-in signal
-func (s *AlertService) isScaleInSignal(data *models.DecisionData) bool {
-	// Check if "Average Down" rule triggered
-	for _, rule := range data.RulesTriggered {
-		if strings.Contains(strings.ToLower(rule.RuleName), "average down") {
-			return true
-		}
+// formatPriceLine renders the latest known price for a symbol, flagging it
+// when the quote is stale. Returns an empty string if no quote is known.
+func (s *AlertService) formatPriceLine(symbol string) string {
+	if s.market == nil {
+		return ""
 	}
-
-	// Check reasoning for scale-in keywords
-	reasoning := strings.ToLower(data.PrimaryReasoning)
-	scaleInKeywords := []string{"average down", "scale-in", "scale in", "adding to position"}
-	for _, keyword := range scaleInKeywords {
-		if strings.Contains(reasoning, keyword) {
-			return true
-		}
+	snap, ok := s.market.Get(symbol)
+	if !ok || !snap.HasQuote() {
+		return ""
 	}
 
-	return false
+	if snap.IsStale(time.Now(), s.market.MaxAge()) {
+		age := time.Since(snap.QuoteUpdatedAt).Round(time.Minute)
+		return fmt.Sprintf("💵 Price: $%.2f <i>(stale, %s old)</i>\n", snap.Price, age)
+	}
+	return fmt.Sprintf("💵 Price: $%.2f\n", snap.Price)
 }
 
 // formatRankingMessage formats a ranking event into a Telegram message
-func (s *AlertService) formatRankingMessage(event *models.RankingEvent) string {
+func (s *AlertService) formatRankingMessage(event *models.RankingEvent, trends map[string]string) string {
 	data := event.Data
 
 	// Signal emoji
@@ -302,6 +542,9 @@ func (s *AlertService) formatRankingMessage(event *models.RankingEvent) string {
 
 		sb.WriteString(fmt.Sprintf("%s <b>%s</b> - Score: %.2f (%.0f%% confidence)\n",
 			medal, r.Symbol, r.Score, r.Confidence*100))
+		if trend := trends[r.Symbol]; trend != "" {
+			sb.WriteString(fmt.Sprintf("    %s\n", trend))
+		}
 
 		if r.Reasoning != "" {
 			// Truncate long reasoning
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/schedule"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// channelDigestCheckInterval is how often digest schedules are checked
+const channelDigestCheckInterval = 30 * time.Second
+
+func newChannelDigests(cfg *config.Config) map[string]*schedule.Recurrence {
+	// The config was validated on load
+	loc, err := schedule.LoadLocation(cfg.DigestTimezone)
+	if err != nil {
+		log.Printf("Warning: channel digests disabled: %v", err)
+		return nil
+	}
+	digests, err := schedule.ParseRecurrences(cfg.ChannelDigests, loc)
+	if err != nil {
+		log.Printf("Warning: channel digests disabled: %v", err)
+		return nil
+	}
+	return digests
+}
+
+// checkDigestChannels warns about digest schedules for unknown channels
+func (s *AlertService) checkDigestChannels() {
+	for channel := range s.digests {
+		if !containsString(s.notifier.DefaultChannels(), channel) {
+			log.Printf("Warning: digest configured for unknown channel %q", channel)
+		}
+	}
+}
+
+// splitChannels divides channels into real-time channels and channels in
+// digest mode. Breakthrough alerts go out everywhere at once.
+func (s *AlertService) splitChannels(ctx context.Context, channels []string) (realtime, batched []string) {
+	for _, channel := range channels {
+		if _, ok := s.digests[channel]; ok && !isBreakthrough(ctx) {
+			batched = append(batched, channel)
+			continue
+		}
+		realtime = append(realtime, channel)
+	}
+	return realtime, batched
+}
+
+// batchForDigest queues a decision for each digest channel. The history
+// record ID lets the digest refer back to the full alert.
+func (s *AlertService) batchForDigest(ctx context.Context, record *storage.AlertRecord, channels []string) {
+	for _, channel := range channels {
+		err := s.queue.EnqueueAlert(ctx, &storage.QueuedAlert{
+			Channel:    channel,
+			RecordID:   record.ID,
+			Kind:       record.Kind,
+			Symbol:     record.Symbol,
+			Signal:     record.Signal,
+			Confidence: record.Confidence,
+			Message:    record.Message,
+			EventTime:  record.EventTime,
+		})
+		if err != nil {
+			log.Printf("Failed to queue %s alert for %s digest: %v", record.Symbol, channel, err)
+		}
+	}
+}
+
+// RunChannelDigests sends each digest channel's batched decisions on its
+// schedule until the context is cancelled. Digests due during quiet hours
+// wait for the next scheduled time after quiet hours end.
+func (s *AlertService) RunChannelDigests(ctx context.Context) {
+	if len(s.digests) == 0 {
+		return
+	}
+
+	now := time.Now()
+	next := make(map[string]time.Time, len(s.digests))
+	for channel, recurrence := range s.digests {
+		next[channel] = recurrence.Next(now)
+		log.Printf("  %s digest: %s, next at %s", channel, recurrence, next[channel].Format("15:04 MST"))
+	}
+
+	ticker := time.NewTicker(channelDigestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+
+		for channel, recurrence := range s.digests {
+			if now.Before(next[channel]) {
+				continue
+			}
+			next[channel] = recurrence.Next(now)
+			if s.isQuietHours(ctx) {
+				continue
+			}
+			if err := s.flushChannelDigest(ctx, channel); err != nil {
+				log.Printf("Failed to deliver %s digest: %v", channel, err)
+			}
+		}
+	}
+}
+
+// flushChannelDigest sends and clears a channel's batched decisions. They
+// are kept when delivery fails so the next digest includes them.
+func (s *AlertService) flushChannelDigest(ctx context.Context, channel string) error {
+	queued, err := s.queue.ListQueuedAlerts(ctx, channel)
+	if err != nil {
+		return err
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+
+	message := s.formatChannelDigest(channel, queued)
+	record := &storage.AlertRecord{
+		Kind:      storage.KindDigest,
+		Message:   message,
+		EventTime: queued[len(queued)-1].QueuedAt,
+	}
+
+	deliveries, err := s.notifier.Send(ctx, message, []string{channel})
+	record.SetDeliveries(deliveries)
+	s.recordAlert(ctx, record)
+	if record.Status == storage.StatusFailed {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+
+	ids := make([]int64, len(queued))
+	for i, alert := range queued {
+		ids[i] = alert.ID
+	}
+	if err := s.queue.DeleteQueuedAlerts(ctx, ids); err != nil {
+		return err
+	}
+
+	log.Printf("Sent %s digest with %d signal(s)", channel, len(queued))
+	return nil
+}
+
+// digestSignalOrder is the order signal groups appear in a digest
+var digestSignalOrder = []string{models.SignalSell, models.SignalBuy, models.SignalWatch}
+
+// formatChannelDigest groups batched decisions by signal, highest confidence
+// first. Only the latest alert per symbol and signal is kept.
+func (s *AlertService) formatChannelDigest(channel string, queued []storage.QueuedAlert) string {
+	latest := make(map[string]storage.QueuedAlert)
+	for _, alert := range queued {
+		latest[alert.Symbol+":"+alert.Signal] = alert
+	}
+
+	groups := make(map[string][]storage.QueuedAlert)
+	for _, alert := range latest {
+		groups[alert.Signal] = append(groups[alert.Signal], alert)
+	}
+
+	order := append([]string(nil), digestSignalOrder...)
+	var others []string
+	for signal := range groups {
+		if !containsString(order, signal) {
+			others = append(others, signal)
+		}
+	}
+	sort.Strings(others)
+	order = append(order, others...)
+
+	loc := s.digests[channel].Location()
+	hasDetail := false
+
+	var sb strings.Builder
+	sb.WriteString("📋 <b>Signal Digest</b>\n")
+	sb.WriteString(fmt.Sprintf("<i>%d signal(s) since %s</i>\n", len(latest),
+		queued[0].QueuedAt.In(loc).Format("Mon 15:04")))
+
+	for _, signal := range order {
+		alerts := groups[signal]
+		if len(alerts) == 0 {
+			continue
+		}
+		sort.Slice(alerts, func(i, j int) bool {
+			if alerts[i].Confidence != alerts[j].Confidence {
+				return alerts[i].Confidence > alerts[j].Confidence
+			}
+			return alerts[i].Symbol < alerts[j].Symbol
+		})
+
+		sb.WriteString(fmt.Sprintf("\n%s <b>%s</b> (%d)\n", signalEmoji(signal), signal, len(alerts)))
+		for _, alert := range alerts {
+			sb.WriteString(fmt.Sprintf("%s %.0f%% · %s", alert.Symbol, alert.Confidence*100,
+				alert.QueuedAt.In(loc).Format("15:04")))
+			if alert.RecordID > 0 {
+				sb.WriteString(fmt.Sprintf(" · #%d", alert.RecordID))
+				hasDetail = true
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Only the Telegram bot answers /detail
+	if hasDetail && channel == telegramChannel && s.config.TelegramCommands {
+		sb.WriteString("\n<i>Send /detail &lt;id&gt; for the full alert</i>")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// DetailCommand answers /detail <id> with the full alert from history
+func (s *AlertService) DetailCommand(ctx context.Context, chatID int64, args []string) (string, error) {
+	if s.store == nil {
+		return "", fmt.Errorf("alert details need a storage backend")
+	}
+	if len(args) != 1 {
+		return "Usage: /detail &lt;id&gt;", nil
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid alert id %q", args[0])
+	}
+
+	record, err := s.store.GetAlert(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if record == nil {
+		return "", fmt.Errorf("alert #%d not found", id)
+	}
+	return record.Message, nil
+}
+
+// IsSubscriber reports whether a chat may use bot commands: any enabled
+// subscriber, or the configured chat without a storage backend
+func (s *AlertService) IsSubscriber(ctx context.Context, chatID int64) bool {
+	if s.store == nil {
+		return chatID == s.config.TelegramChatID
+	}
+
+	subscribers, err := s.store.ListSubscribers(ctx)
+	if err != nil {
+		log.Printf("Failed to list subscribers: %v", err)
+		return chatID == s.config.TelegramChatID
+	}
+	for _, sub := range subscribers {
+		if sub.Enabled && sub.ChatID == chatID {
+			return true
+		}
+	}
+	return false
+}
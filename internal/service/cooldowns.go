@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/cooldown"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+	"github.com/trogers1052/alert-service/internal/watchlist"
+)
+
+// cooldownLoadTimeout bounds the startup read of persisted cooldowns
+const cooldownLoadTimeout = 10 * time.Second
+
+// newCooldownPolicy builds the decision alert cooldown policy from config
+func newCooldownPolicy(cfg *config.Config, lists *watchlist.Set) cooldown.Policy {
+	policy := cooldown.Policy{
+		Default:                 time.Duration(cfg.CooldownMinutes) * time.Minute,
+		Signals:                 make(map[string]time.Duration),
+		Symbols:                 make(map[string]time.Duration),
+		BypassOnDirectionChange: cfg.CooldownDirectionBypass,
+		ConfidenceJump:          cfg.CooldownConfidenceJump,
+		Adaptive:                cfg.CooldownAdaptive,
+		Window:                  time.Duration(cfg.CooldownAdaptiveWindow) * time.Minute,
+		Max:                     time.Duration(cfg.CooldownAdaptiveMax) * time.Minute,
+	}
+	for signal, minutes := range cfg.CooldownSignalMinutes {
+		policy.Signals[signal] = time.Duration(minutes) * time.Minute
+	}
+	for symbol, minutes := range cfg.CooldownSymbolMinutes {
+		policy.Symbols[symbol] = time.Duration(minutes) * time.Minute
+	}
+	if len(cfg.CooldownWatchlistMinutes) > 0 {
+		policy.Lists = make(map[string]time.Duration)
+		for name, minutes := range cfg.CooldownWatchlistMinutes {
+			policy.Lists[watchlist.NormalizeName(name)] = time.Duration(minutes) * time.Minute
+		}
+		policy.ListsOf = lists.ListsOf
+	}
+	return policy
+}
+
+// checkDecisionCooldown applies the cooldown policy to a decision. The
+// symbol and signal key holds the last alert for that signal; the bare
+// symbol key holds the symbol's most recent alert of any signal.
+func (s *AlertService) checkDecisionCooldown(data *models.DecisionData) cooldown.Decision {
+	s.cooldownMu.RLock()
+	same := s.cooldownEntry(cooldown.Key(data.Symbol, data.Signal))
+	latest := s.cooldownEntry(data.Symbol)
+	s.cooldownMu.RUnlock()
+
+	return s.policy.Check(time.Now(), data.Symbol, data.Signal, data.Confidence, same, latest)
+}
+
+// setDecisionCooldown starts the cooldown for a sent decision alert,
+// escalating it when the symbol keeps re-alerting. The level is read and
+// written under one lock so concurrent alerts for a symbol both escalate.
+func (s *AlertService) setDecisionCooldown(ctx context.Context, data *models.DecisionData) {
+	key := cooldown.Key(data.Symbol, data.Signal)
+	now := time.Now()
+
+	s.cooldownMu.Lock()
+	level := s.policy.NextLevel(now, data.Symbol, data.Signal, s.cooldownEntry(key))
+	entry := storage.Cooldown{Key: key, LastAlert: now, Signal: data.Signal, Confidence: data.Confidence, Level: level}
+	latest := entry
+	latest.Key = data.Symbol
+	s.cooldowns[entry.Key] = entry
+	s.cooldowns[latest.Key] = latest
+	s.cooldownMu.Unlock()
+
+	if level > 0 {
+		log.Printf("Cooldown for %s %s escalated to %s", data.Symbol, data.Signal,
+			s.policy.Effective(data.Symbol, data.Signal, level))
+	}
+
+	s.persistCooldown(ctx, entry)
+	s.persistCooldown(ctx, latest)
+}
+
+// cooldownEntry returns the policy entry for key, or nil if there is none.
+// Callers must hold s.cooldownMu.
+func (s *AlertService) cooldownEntry(key string) *cooldown.Entry {
+	last, ok := s.cooldowns[key]
+	if !ok {
+		return nil
+	}
+	return &cooldown.Entry{At: last.LastAlert, Signal: last.Signal, Confidence: last.Confidence, Level: last.Level}
+}
+
+// checkCooldownKey returns true if the cooldown for key has elapsed
+func (s *AlertService) checkCooldownKey(key string, cooldownDuration time.Duration) bool {
+	s.cooldownMu.RLock()
+	last, exists := s.cooldowns[key]
+	s.cooldownMu.RUnlock()
+
+	if !exists {
+		return true
+	}
+
+	return time.Since(last.LastAlert) >= cooldownDuration
+}
+
+// setCooldown records the last alert for a cooldown key and persists it
+func (s *AlertService) setCooldown(ctx context.Context, entry storage.Cooldown) {
+	s.cooldownMu.Lock()
+	s.cooldowns[entry.Key] = entry
+	s.cooldownMu.Unlock()
+
+	s.persistCooldown(ctx, entry)
+}
+
+// persistCooldown writes a cooldown entry to the store. A failed write is
+// logged; the in-memory cooldown still applies.
+func (s *AlertService) persistCooldown(ctx context.Context, entry storage.Cooldown) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.SaveCooldown(ctx, entry); err != nil {
+		log.Printf("Failed to persist cooldown for %s: %v", entry.Key, err)
+	}
+}
+
+// cooldownRetention is how long a cooldown entry is kept; older entries
+// can no longer suppress an alert. It is extended to cover the longest
+// custom rule cooldown.
+func (s *AlertService) cooldownRetention() time.Duration {
+	retention := time.Duration(s.config.CooldownRetentionHours) * time.Hour
+	if s.rules != nil {
+		for _, rule := range s.rules.Rules() {
+			if d := time.Duration(rule.CooldownMinutes) * time.Minute; d > retention {
+				retention = d
+			}
+		}
+	}
+	return retention
+}
+
+// loadCooldowns restores persisted cooldowns, skipping expired entries
+func (s *AlertService) loadCooldowns() {
+	if s.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cooldownLoadTimeout)
+	defer cancel()
+
+	s.ExpireCooldowns(ctx)
+
+	cooldowns, err := s.store.LoadCooldowns(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to load cooldowns, starting with none: %v", err)
+		return
+	}
+
+	cutoff := time.Now().Add(-s.cooldownRetention())
+	s.cooldownMu.Lock()
+	for _, entry := range cooldowns {
+		if entry.LastAlert.After(cutoff) {
+			s.cooldowns[entry.Key] = entry
+		}
+	}
+	loaded := len(s.cooldowns)
+	s.cooldownMu.Unlock()
+
+	log.Printf("Restored %d cooldown(s)", loaded)
+}
+
+// ExpireCooldowns drops cooldown entries older than the retention period
+// from memory and from the store
+func (s *AlertService) ExpireCooldowns(ctx context.Context) {
+	cutoff := time.Now().Add(-s.cooldownRetention())
+
+	s.cooldownMu.Lock()
+	for key, entry := range s.cooldowns {
+		if entry.LastAlert.Before(cutoff) {
+			delete(s.cooldowns, key)
+		}
+	}
+	s.cooldownMu.Unlock()
+
+	if s.store == nil {
+		return
+	}
+	removed, err := s.store.DeleteCooldownsBefore(ctx, cutoff)
+	if err != nil {
+		log.Printf("Failed to expire cooldowns: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("Expired %d cooldown(s)", removed)
+	}
+}
+
+// RunCooldownExpiry expires old cooldowns every interval until the context
+// is cancelled
+func (s *AlertService) RunCooldownExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.ExpireCooldowns(ctx)
+		}
+	}
+}
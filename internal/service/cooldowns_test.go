@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/cooldown"
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+func TestSetDecisionCooldownEscalatesConcurrently(t *testing.T) {
+	s, _ := newTestService(t, map[string]string{
+		"COOLDOWN_MINUTES":                 "30",
+		"COOLDOWN_ADAPTIVE":                "true",
+		"COOLDOWN_ADAPTIVE_WINDOW_MINUTES": "720",
+		"COOLDOWN_ADAPTIVE_MAX_MINUTES":    "100000",
+	}, nil)
+
+	const alerts = 8
+	data := &models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy, Confidence: 0.8}
+	var wg sync.WaitGroup
+	for i := 0; i < alerts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.setDecisionCooldown(context.Background(), data)
+		}()
+	}
+	wg.Wait()
+
+	// Every alert after the first escalates once
+	if got := s.cooldowns[cooldown.Key("AAPL", models.SignalBuy)].Level; got != alerts-1 {
+		t.Errorf("level after %d concurrent alerts = %d, want %d", alerts, got, alerts-1)
+	}
+	if got := s.Status().Cooldowns; len(got) != 1 || got[0].Level != alerts-1 {
+		t.Errorf("Status() cooldowns = %+v, want one entry at level %d", got, alerts-1)
+	}
+}
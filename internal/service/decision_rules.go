@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// checkDecisionRules applies the filters on a decision's triggered rules.
+// It returns the suppression reason and why, or an empty reason when the
+// decision passes. Rules below their minimum confidence do not count
+// towards the required rules or the agreeing rules.
+func (s *AlertService) checkDecisionRules(data *models.DecisionData) (string, string) {
+	cfg := s.config
+	if len(cfg.DecisionRulesRequire) == 0 && len(cfg.DecisionRulesExclude) == 0 &&
+		len(cfg.DecisionRulesMinConfidence) == 0 && cfg.DecisionRulesMinAgreeing <= 0 {
+		return "", ""
+	}
+
+	triggered := make(map[string]bool) // every triggered rule
+	counted := make(map[string]bool)   // rules meeting their minimum confidence
+	for _, rule := range data.RulesTriggered {
+		name := strings.ToLower(strings.TrimSpace(rule.RuleName))
+		if containsString(cfg.DecisionRulesExclude, name) {
+			return storage.ReasonRuleExcluded, fmt.Sprintf("rule %s is excluded", rule.RuleName)
+		}
+		triggered[name] = true
+		if minimum, ok := cfg.DecisionRulesMinConfidence[name]; !ok || rule.Confidence >= minimum {
+			counted[name] = true
+		}
+	}
+
+	if len(cfg.DecisionRulesRequire) > 0 && !anyRule(counted, cfg.DecisionRulesRequire) {
+		if anyRule(triggered, cfg.DecisionRulesRequire) {
+			return storage.ReasonRuleConfidence, "required rules are below their minimum confidence"
+		}
+		return storage.ReasonRuleMissing, fmt.Sprintf("none of %s triggered", strings.Join(cfg.DecisionRulesRequire, ", "))
+	}
+
+	if n := cfg.DecisionRulesMinAgreeing; n > 0 && len(counted) < n {
+		return storage.ReasonTooFewRules, fmt.Sprintf("%d rules agree, %d needed", len(counted), n)
+	}
+
+	if len(counted) == 0 && len(triggered) > 0 {
+		return storage.ReasonRuleConfidence, "every rule is below its minimum confidence"
+	}
+	return "", ""
+}
+
+// anyRule reports whether any of the named rules is in rules
+func anyRule(rules map[string]bool, names []string) bool {
+	for _, name := range names {
+		if rules[name] {
+			return true
+		}
+	}
+	return false
+}
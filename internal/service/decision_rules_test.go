@@ -0,0 +1,64 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// triggeredRules parses "rsi_oversold:0.6 macd_cross" into rule results;
+// rules without a confidence get 0.8
+func triggeredRules(t *testing.T, spec string) []models.RuleResult {
+	t.Helper()
+	var results []models.RuleResult
+	for _, field := range strings.Fields(spec) {
+		name, confidence, found := strings.Cut(field, ":")
+		result := models.RuleResult{RuleName: name, Confidence: 0.8}
+		if found {
+			var err error
+			if result.Confidence, err = strconv.ParseFloat(confidence, 64); err != nil {
+				t.Fatalf("bad rule %q: %v", field, err)
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestCheckDecisionRules(t *testing.T) {
+	require := func(names ...string) config.Config { return config.Config{DecisionRulesRequire: names} }
+	minimums := map[string]float64{"rsi_oversold": 0.7, "macd_cross": 0.9}
+
+	for i, c := range []struct {
+		cfg       config.Config
+		triggered string
+		want      string
+	}{
+		{config.Config{}, "rsi_oversold:0.1", ""},
+		{config.Config{DecisionRulesExclude: []string{"earnings_soon"}}, "rsi_oversold Earnings_Soon ", storage.ReasonRuleExcluded},
+		{require("rsi_oversold", "macd_cross"), "volume_spike RSI_Oversold", ""},
+		{require("rsi_oversold", "macd_cross"), "volume_spike", storage.ReasonRuleMissing},
+		{config.Config{DecisionRulesRequire: []string{"rsi_oversold"}, DecisionRulesMinConfidence: minimums}, "rsi_oversold:0.6 volume_spike:0.9", storage.ReasonRuleConfidence},
+		{config.Config{DecisionRulesRequire: []string{"rsi_oversold"}, DecisionRulesMinConfidence: minimums}, "rsi_oversold:0.7", ""},
+		{config.Config{DecisionRulesMinAgreeing: 2}, "rsi_oversold macd_cross", ""},
+		{config.Config{DecisionRulesMinAgreeing: 2}, "rsi_oversold rsi_oversold", storage.ReasonTooFewRules},
+		{config.Config{DecisionRulesMinAgreeing: 2, DecisionRulesMinConfidence: minimums}, "rsi_oversold macd_cross", storage.ReasonTooFewRules},
+		{config.Config{DecisionRulesMinConfidence: minimums}, "rsi_oversold:0.5 macd_cross", storage.ReasonRuleConfidence},
+		{config.Config{DecisionRulesMinConfidence: minimums}, "macd_cross volume_spike", ""},
+		{config.Config{DecisionRulesMinConfidence: minimums}, "", ""},
+	} {
+		s := &AlertService{config: &c.cfg}
+		data := &models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy, RulesTriggered: triggeredRules(t, c.triggered)}
+		reason, why := s.checkDecisionRules(data)
+		if reason != c.want {
+			t.Errorf("case %d (%q): checkDecisionRules() = %q (%s), want %q", i, c.triggered, reason, why, c.want)
+		}
+		if (reason == "") != (why == "") {
+			t.Errorf("case %d: reason %q with explanation %q", i, reason, why)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// breakthroughKey marks a context whose alert must reach every subscriber,
+// including those in quiet hours
+type breakthroughKey struct{}
+
+func withBreakthrough(ctx context.Context) context.Context {
+	return context.WithValue(ctx, breakthroughKey{}, true)
+}
+
+func isBreakthrough(ctx context.Context) bool {
+	v, _ := ctx.Value(breakthroughKey{}).(bool)
+	return v
+}
+
+// breaksThroughQuietHours reports whether a decision is urgent enough to be
+// sent during quiet hours
+func (s *AlertService) breaksThroughQuietHours(data *models.DecisionData) bool {
+	threshold := s.config.QuietHoursSellBreakthrough
+	return threshold > 0 && data.Signal == models.SignalSell && data.Confidence >= threshold
+}
+
+// telegramChannel is the notification channel quiet hours apply to per chat
+const telegramChannel = "telegram"
+
+// holdForDigest holds an alert that arrived while every chat was in quiet
+// hours. Real-time channels get it in the quiet hours digest, channels in
+// digest mode in their next digest. It is suppressed when quiet hours
+// digests are disabled or nothing could be queued.
+func (s *AlertService) holdForDigest(ctx context.Context, record *storage.AlertRecord, realtime, batched []string) {
+	if !s.config.QuietHoursDigest {
+		s.suppress(ctx, record, storage.ReasonQuietHours)
+		return
+	}
+
+	queued := 0
+	for _, channel := range realtime {
+		if channel == telegramChannel {
+			queued += s.queueQuiet(ctx, record, channel, s.quietChats(ctx)...)
+			continue
+		}
+		queued += s.queueQuiet(ctx, record, channel, 0)
+	}
+	if queued == 0 && len(batched) == 0 {
+		s.suppress(ctx, record, storage.ReasonQuietHours)
+		return
+	}
+
+	record.Queue(storage.ReasonQuietHours)
+	s.recordAlert(ctx, record)
+	s.batchForDigest(ctx, record, batched)
+}
+
+// holdForQuietChats queues an alert sent live for the chats that are in
+// their own quiet hours, so their digest includes it. Other channels only
+// hold alerts while every chat is quiet.
+func (s *AlertService) holdForQuietChats(ctx context.Context, record *storage.AlertRecord, realtime []string) {
+	if !s.config.QuietHoursDigest || !containsString(realtime, telegramChannel) {
+		return
+	}
+	if chats := s.quietChats(ctx); len(chats) > 0 {
+		s.queueQuiet(ctx, record, telegramChannel, chats...)
+	}
+}
+
+// queueQuiet queues an alert for the quiet hours digest of a channel, once
+// per chat for Telegram, and returns how many were queued
+func (s *AlertService) queueQuiet(ctx context.Context, record *storage.AlertRecord, channel string, chats ...int64) int {
+	signal := record.Signal
+	if record.Kind == storage.KindRule {
+		signal = record.RuleID // rule alerts are told apart by rule
+	}
+
+	queued := 0
+	for _, chat := range chats {
+		err := s.queue.EnqueueAlert(ctx, &storage.QueuedAlert{
+			Target:     channel,
+			ChatID:     chat,
+			Kind:       record.Kind,
+			Symbol:     record.Symbol,
+			Signal:     signal,
+			Confidence: record.Confidence,
+			Message:    record.Message,
+			EventTime:  record.EventTime,
+		})
+		if err != nil {
+			log.Printf("Failed to queue %s alert %s for %s digest: %v", record.Kind, record.Symbol, quietRecipient(channel, chat), err)
+			continue
+		}
+		queued++
+	}
+	return queued
+}
+
+// quietRecipient names a quiet hours digest recipient in logs and errors
+func quietRecipient(channel string, chat int64) string {
+	if chat != 0 {
+		return fmt.Sprintf("%s chat %d", channel, chat)
+	}
+	return channel
+}
+
+// RunDigest delivers queued alerts as one digest per chat once its quiet
+// hours are over, checking every interval until the context is cancelled
+func (s *AlertService) RunDigest(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.flushDigest(ctx); err != nil {
+			log.Printf("Failed to deliver quiet hours digest: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// flushDigest sends each recipient's queued alerts once its quiet hours
+// have ended: Telegram chats by their own schedule, other channels once any
+// chat is out of quiet hours, or by the global schedule without enabled
+// subscribers, as their live alerts are. Only delivered
+// alerts are removed, so a failed recipient is retried on the next check.
+func (s *AlertService) flushDigest(ctx context.Context) error {
+	queued, err := s.queue.ListQueuedAlerts(ctx, "")
+	if err != nil {
+		return err
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	active, quiet, err := s.quiet.Partition(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	type recipient struct {
+		channel string
+		chat    int64
+	}
+	var recipients []recipient
+	byRecipient := make(map[recipient][]storage.QueuedAlert)
+	for _, alert := range queued {
+		r := recipient{channel: alert.Target, chat: alert.ChatID}
+		if _, ok := byRecipient[r]; !ok {
+			recipients = append(recipients, r)
+		}
+		byRecipient[r] = append(byRecipient[r], alert)
+	}
+
+	var errs []error
+	for _, r := range recipients {
+		alerts := byRecipient[r]
+		to := quietRecipient(r.channel, r.chat)
+		var err error
+		switch {
+		case !containsString(s.notifier.DefaultChannels(), r.channel):
+			log.Printf("Dropping %d queued alert(s) for %s: unknown channel", len(alerts), to)
+			err = s.queue.DeleteQueuedAlerts(ctx, queuedIDs(alerts))
+		case r.channel != telegramChannel:
+			if s.quiet.allQuiet(active, quiet, now) {
+				continue
+			}
+			err = s.sendDigest(ctx, alerts, r.channel, to)
+		case containsChat(active, r.chat):
+			err = s.sendDigest(withRecipients(ctx, []int64{r.chat}), alerts, r.channel, to)
+		case containsChat(quiet, r.chat):
+			continue
+		default:
+			log.Printf("Dropping %d queued alert(s) for %s: no longer a recipient", len(alerts), to)
+			err = s.queue.DeleteQueuedAlerts(ctx, queuedIDs(alerts))
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendDigest delivers one recipient's queued alerts and removes them
+func (s *AlertService) sendDigest(ctx context.Context, queued []storage.QueuedAlert, channel, to string) error {
+	message := s.formatDigest(queued)
+	record := &storage.AlertRecord{
+		Kind:      storage.KindDigest,
+		Message:   message,
+		EventTime: queued[len(queued)-1].QueuedAt,
+	}
+
+	deliveries, err := s.notifier.Send(ctx, message, []string{channel})
+	record.SetDeliveries(deliveries)
+	s.recordAlert(ctx, record)
+	if record.Status == storage.StatusFailed {
+		return fmt.Errorf("failed to send digest to %s: %w", to, err)
+	}
+
+	if err := s.queue.DeleteQueuedAlerts(ctx, queuedIDs(queued)); err != nil {
+		return err
+	}
+
+	log.Printf("Sent quiet hours digest with %d queued alert(s) to %s", len(queued), to)
+	return nil
+}
+
+func queuedIDs(alerts []storage.QueuedAlert) []int64 {
+	ids := make([]int64, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+	return ids
+}
+
+func containsChat(chats []int64, chat int64) bool {
+	for _, c := range chats {
+		if c == chat {
+			return true
+		}
+	}
+	return false
+}
+
+// formatDigest consolidates queued alerts into one message. Decisions are
+// de-duplicated per symbol so only the latest signal is shown; rankings
+// keep the latest message per signal type.
+func (s *AlertService) formatDigest(queued []storage.QueuedAlert) string {
+	var decisions []storage.QueuedAlert
+	latestDecision := make(map[string]int) // symbol -> index into decisions
+	var rankings []storage.QueuedAlert
+	latestRanking := make(map[string]int) // signal type -> index into rankings
+	var ruleAlerts []storage.QueuedAlert
+	latestRule := make(map[string]int) // rule ID + symbol -> index into ruleAlerts
+
+	// queued is oldest first, so later alerts replace earlier ones in place
+	for _, alert := range queued {
+		switch alert.Kind {
+		case storage.KindRanking:
+			if i, ok := latestRanking[alert.Signal]; ok {
+				rankings[i] = alert
+				continue
+			}
+			latestRanking[alert.Signal] = len(rankings)
+			rankings = append(rankings, alert)
+		case storage.KindRule:
+			key := alert.Signal + "|" + alert.Symbol
+			if i, ok := latestRule[key]; ok {
+				ruleAlerts[i] = alert
+				continue
+			}
+			latestRule[key] = len(ruleAlerts)
+			ruleAlerts = append(ruleAlerts, alert)
+		default:
+			if i, ok := latestDecision[alert.Symbol]; ok {
+				decisions[i] = alert
+				continue
+			}
+			latestDecision[alert.Symbol] = len(decisions)
+			decisions = append(decisions, alert)
+		}
+	}
+
+	loc := s.quiet.location()
+
+	var sb strings.Builder
+	sb.WriteString("🌅 <b>Quiet Hours Digest</b>\n")
+	sb.WriteString(fmt.Sprintf("<i>%d alert(s) held during quiet hours</i>\n", len(queued)))
+
+	if len(decisions) > 0 {
+		sb.WriteString("\n<b>Signals</b>\n")
+		for _, alert := range decisions {
+			sb.WriteString(fmt.Sprintf("%s <b>%s</b> %s %.0f%% · %s\n",
+				signalEmoji(alert.Signal), alert.Symbol, alert.Signal, alert.Confidence*100,
+				alert.QueuedAt.In(loc).Format("Mon 15:04")))
+		}
+		if superseded := countKind(queued, storage.KindDecision) - len(decisions); superseded > 0 {
+			sb.WriteString(fmt.Sprintf("<i>%d earlier signal(s) superseded</i>\n", superseded))
+		}
+	}
+
+	if len(ruleAlerts) > 0 {
+		sb.WriteString("\n<b>Rules</b>\n")
+		for _, alert := range ruleAlerts {
+			sb.WriteString(fmt.Sprintf("🚨 <b>%s</b> %s · %s\n",
+				alert.Symbol, html.EscapeString(alert.Signal), alert.QueuedAt.In(loc).Format("Mon 15:04")))
+		}
+	}
+
+	for _, alert := range rankings {
+		sb.WriteString("\n")
+		sb.WriteString(alert.Message)
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func countKind(alerts []storage.QueuedAlert, kind string) int {
+	n := 0
+	for _, alert := range alerts {
+		if alert.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// signalEmoji returns the emoji used for a signal in compact listings
+func signalEmoji(signal string) string {
+	switch signal {
+	case models.SignalBuy:
+		return "🟢"
+	case models.SignalSell:
+		return "🔴"
+	case models.SignalWatch:
+		return "👀"
+	default:
+		return "•"
+	}
+}
+
+// memoryQueue holds queued alerts when no storage backend is configured.
+// Its contents are lost on restart.
+type memoryQueue struct {
+	mu     sync.Mutex
+	nextID int64
+	alerts []storage.QueuedAlert
+}
+
+func (q *memoryQueue) EnqueueAlert(ctx context.Context, alert *storage.QueuedAlert) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.nextID++
+	alert.ID = q.nextID
+	alert.QueuedAt = time.Now()
+	q.alerts = append(q.alerts, *alert)
+	return nil
+}
+
+func (q *memoryQueue) ListQueuedAlerts(ctx context.Context, channel string) ([]storage.QueuedAlert, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var alerts []storage.QueuedAlert
+	for _, alert := range q.alerts {
+		if alert.Channel == channel {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+func (q *memoryQueue) DeleteQueuedAlerts(ctx context.Context, ids []int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	remove := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+	kept := q.alerts[:0]
+	for _, alert := range q.alerts {
+		if !remove[alert.ID] {
+			kept = append(kept, alert)
+		}
+	}
+	q.alerts = kept
+	return nil
+}
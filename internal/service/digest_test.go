@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+func testRecord(symbol string) *storage.AlertRecord {
+	return &storage.AlertRecord{Kind: storage.KindDecision, Symbol: symbol, Signal: models.SignalBuy,
+		Confidence: 0.8, Message: symbol + " BUY"}
+}
+
+// setQuietChats saves chats 1 and 2 as subscribers, giving the listed ones
+// an all-day quiet schedule
+func setQuietChats(t *testing.T, store storage.SubscriberStore, quiet ...int64) {
+	t.Helper()
+	for _, chat := range []int64{1, 2} {
+		sub := storage.Subscriber{ChatID: chat, Enabled: true}
+		if containsChat(quiet, chat) {
+			sub.QuietHours = "daily all"
+		}
+		if err := store.SaveSubscriber(context.Background(), sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// queuedCounts counts the queued alerts per quiet hours recipient, or per
+// digest channel for channel digests
+func queuedCounts(t *testing.T, store storage.QueueStore, channel string) map[string]int {
+	t.Helper()
+	queued, err := store.ListQueuedAlerts(context.Background(), channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, alert := range queued {
+		if channel != "" {
+			counts[alert.Channel]++
+			continue
+		}
+		counts[quietRecipient(alert.Target, alert.ChatID)]++
+	}
+	return counts
+}
+
+func TestQuietHoursDigestPerChat(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	email := &fakeNotifier{name: "email"}
+	s, telegram := newTestService(t, map[string]string{"QUIET_HOURS_DIGEST": "true"}, store, email)
+	channels := []string{"telegram", "email"}
+
+	flush := func() {
+		t.Helper()
+		telegram.reset()
+		email.reset()
+		if err := s.flushDigest(ctx); err != nil {
+			t.Fatalf("flushDigest: %v", err)
+		}
+	}
+
+	// Chat 1 is quiet, chat 2 and email get the alert live
+	setQuietChats(t, store, 1)
+	if s.isQuietHours(ctx) {
+		t.Fatal("isQuietHours() = true with chat 2 outside quiet hours")
+	}
+	s.holdForQuietChats(ctx, testRecord("AAPL"), channels)
+	if got := queuedCounts(t, store, ""); len(got) != 1 || got["telegram chat 1"] != 1 {
+		t.Fatalf("queued after a partly quiet alert = %v, want one alert for chat 1", got)
+	}
+
+	flush()
+	if len(telegram.messages()) != 0 || queuedCounts(t, store, "")["telegram chat 1"] != 1 {
+		t.Fatal("digest sent while chat 1 is still quiet")
+	}
+
+	// Everyone quiet: queued for each chat and for email
+	setQuietChats(t, store, 1, 2)
+	record := testRecord("MSFT")
+	s.holdForDigest(ctx, record, channels, nil)
+	if record.Status != storage.StatusQueued {
+		t.Errorf("record status = %q, want %q", record.Status, storage.StatusQueued)
+	}
+	if got := queuedCounts(t, store, ""); got["telegram chat 1"] != 2 || got["telegram chat 2"] != 1 || got["email"] != 1 {
+		t.Fatalf("queued while everyone is quiet = %v, want 2 for chat 1, 1 for chat 2 and email", got)
+	}
+
+	// Chat 2 wakes up: it and email get their digests, chat 1 keeps waiting
+	setQuietChats(t, store, 1)
+	flush()
+	if len(telegram.chats) != 1 || len(telegram.chats[0]) != 1 || telegram.chats[0][0] != 2 {
+		t.Errorf("telegram digest went to %v, want only chat 2", telegram.chats)
+	}
+	if len(email.messages()) != 1 {
+		t.Errorf("email got %d digests, want 1", len(email.messages()))
+	}
+	if got := queuedCounts(t, store, ""); len(got) != 1 || got["telegram chat 1"] != 2 {
+		t.Fatalf("queued after chat 2's digest = %v, want chat 1's two alerts", got)
+	}
+
+	// Chat 1 wakes up and gets both alerts
+	setQuietChats(t, store)
+	flush()
+	if len(telegram.chats) != 1 || len(telegram.chats[0]) != 1 || telegram.chats[0][0] != 1 {
+		t.Errorf("telegram digest went to %v, want only chat 1", telegram.chats)
+	}
+	if got := queuedCounts(t, store, ""); len(got) != 0 {
+		t.Errorf("queued after every digest = %v, want empty", got)
+	}
+}
+
+func TestHoldForDigestRouting(t *testing.T) {
+	tests := []struct {
+		name     string
+		digest   string
+		realtime []string
+		batched  []string
+		status   string
+		quiet    map[string]int
+		channel  map[string]int // channel digest queue for email
+	}{
+		{
+			name:     "every channel",
+			digest:   "true",
+			realtime: []string{"telegram", "email"},
+			status:   storage.StatusQueued,
+			quiet:    map[string]int{"telegram chat 1": 1, "telegram chat 2": 1, "email": 1},
+		},
+		{
+			name:     "routed to email only",
+			digest:   "true",
+			realtime: []string{"email"},
+			status:   storage.StatusQueued,
+			quiet:    map[string]int{"email": 1},
+		},
+		{
+			name:     "email in digest mode",
+			digest:   "true",
+			realtime: []string{"telegram"},
+			batched:  []string{"email"},
+			status:   storage.StatusQueued,
+			quiet:    map[string]int{"telegram chat 1": 1, "telegram chat 2": 1},
+			channel:  map[string]int{"email": 1},
+		},
+		{
+			name:     "quiet hours digest disabled",
+			digest:   "false",
+			realtime: []string{"telegram", "email"},
+			status:   storage.StatusSuppressed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newTestStore(t)
+			s, _ := newTestService(t, map[string]string{"QUIET_HOURS_DIGEST": tt.digest}, store,
+				&fakeNotifier{name: "email"})
+			setQuietChats(t, store, 1, 2)
+
+			record := testRecord("AAPL")
+			s.holdForDigest(ctx, record, tt.realtime, tt.batched)
+			if record.Status != tt.status {
+				t.Errorf("status = %q, want %q", record.Status, tt.status)
+			}
+			if got := queuedCounts(t, store, ""); !equalCounts(got, tt.quiet) {
+				t.Errorf("quiet hours queue = %v, want %v", got, tt.quiet)
+			}
+			if got := queuedCounts(t, store, "email"); !equalCounts(got, tt.channel) {
+				t.Errorf("email digest queue = %v, want %v", got, tt.channel)
+			}
+		})
+	}
+}
+
+func equalCounts(got, want map[string]int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for key, n := range want {
+		if got[key] != n {
+			return false
+		}
+	}
+	return true
+}
+
+func TestChannelDigestDetailHint(t *testing.T) {
+	tests := []struct {
+		channel  string
+		commands string
+		hint     bool
+	}{
+		{"telegram", "true", true},
+		{"telegram", "false", false},
+		{"email", "true", false},
+	}
+
+	for _, tt := range tests {
+		s, _ := newTestService(t, map[string]string{
+			"CHANNEL_DIGESTS":   "telegram=every 60m;email=every 60m",
+			"TELEGRAM_COMMANDS": tt.commands,
+		}, nil, &fakeNotifier{name: "email"})
+		queued := []storage.QueuedAlert{{RecordID: 42, Kind: storage.KindDecision, Symbol: "AAPL",
+			Signal: models.SignalBuy, Confidence: 0.8}}
+
+		message := s.formatChannelDigest(tt.channel, queued)
+		if got := strings.Contains(message, "/detail"); got != tt.hint {
+			t.Errorf("%s digest with TELEGRAM_COMMANDS=%s: /detail hint = %v, want %v", tt.channel, tt.commands, got, tt.hint)
+		}
+	}
+}
+
+func TestQuietHoursWithoutSubscribers(t *testing.T) {
+	ctx := context.Background()
+	for _, schedule := range []string{"", "daily all"} {
+		store := newTestStore(t)
+		email := &fakeNotifier{name: "email"}
+		s, _ := newTestService(t, map[string]string{"QUIET_HOURS_DIGEST": "true", "QUIET_HOURS": schedule}, store, email)
+
+		// With no enabled subscribers the global schedule decides
+		quiet := schedule != ""
+		if got := s.isQuietHours(ctx); got != quiet {
+			t.Errorf("QUIET_HOURS=%q: isQuietHours() = %v with no subscribers, want %v", schedule, got, quiet)
+		}
+
+		s.queueQuiet(ctx, testRecord("AAPL"), "email", 0)
+		if err := s.flushDigest(ctx); err != nil {
+			t.Fatalf("flushDigest: %v", err)
+		}
+		if sent := len(email.messages()) == 1; sent == quiet {
+			t.Errorf("QUIET_HOURS=%q: email digest sent = %v with no subscribers", schedule, sent)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/watchlist"
+)
+
+// lastSignal is the most recent BUY or SELL alerted for a symbol, kept by
+// normalized symbol
+type lastSignal struct {
+	Signal     string
+	Confidence float64
+	Reasoning  string
+	EventTime  time.Time // timestamp of the decision event
+	AlertedAt  time.Time
+}
+
+// flipWindow returns how long after an alert an opposite signal for the
+// symbol counts as a reversal
+func (s *AlertService) flipWindow(symbol string) time.Duration {
+	if minutes, ok := s.config.FlipSymbolMinutes[watchlist.NormalizeSymbol(symbol)]; ok {
+		return time.Duration(minutes) * time.Minute
+	}
+	return time.Duration(s.config.FlipWindowMinutes) * time.Minute
+}
+
+// detectFlip returns the symbol's last alerted signal when the decision
+// reverses it within the flip window, or nil
+func (s *AlertService) detectFlip(data *models.DecisionData) *lastSignal {
+	if data.Signal != models.SignalBuy && data.Signal != models.SignalSell {
+		return nil
+	}
+	window := s.flipWindow(data.Symbol)
+	if window <= 0 {
+		return nil
+	}
+
+	s.signalsMu.Lock()
+	last, ok := s.signals[watchlist.NormalizeSymbol(data.Symbol)]
+	s.signalsMu.Unlock()
+
+	if !ok || last.Signal == data.Signal || time.Since(last.AlertedAt) > window {
+		return nil
+	}
+	return &last
+}
+
+// noteSignal remembers an alerted BUY or SELL for flip detection. WATCH
+// decisions leave the last signal in place.
+func (s *AlertService) noteSignal(event *models.DecisionEvent) {
+	data := event.Data
+	if data.Signal != models.SignalBuy && data.Signal != models.SignalSell {
+		return
+	}
+
+	s.signalsMu.Lock()
+	defer s.signalsMu.Unlock()
+	s.signals[watchlist.NormalizeSymbol(data.Symbol)] = lastSignal{
+		Signal:     data.Signal,
+		Confidence: data.Confidence,
+		Reasoning:  data.PrimaryReasoning,
+		EventTime:  event.Timestamp,
+		AlertedAt:  time.Now(),
+	}
+}
+
+// formatFlipMessage renders a decision that reverses the symbol's last
+// signal, showing both decisions side by side
+func (s *AlertService) formatFlipMessage(event *models.DecisionEvent, prev *lastSignal) string {
+	data := event.Data
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔄 <b>SIGNAL REVERSED: %s</b>\n", data.Symbol))
+	sb.WriteString(fmt.Sprintf("%s %s → %s %s after %s\n\n",
+		signalEmoji(prev.Signal), prev.Signal, signalEmoji(data.Signal), data.Signal,
+		formatSpan(time.Since(prev.AlertedAt))))
+
+	// The decision being reversed
+	sb.WriteString(fmt.Sprintf("⏮ <b>Before:</b> %s %.0f%% · %s\n", prev.Signal, prev.Confidence*100,
+		prev.EventTime.Format("15:04 MST")))
+	if prev.Reasoning != "" {
+		sb.WriteString(fmt.Sprintf("💡 %s\n", prev.Reasoning))
+	}
+	sb.WriteString("\n")
+
+	// The new decision
+	sb.WriteString(fmt.Sprintf("⏭ <b>Now:</b> %s %.0f%% %s\n", data.Signal, data.Confidence*100,
+		s.formatConfidenceBar(data.Confidence)))
+	sb.WriteString(s.formatTrendLine(&data))
+	if data.PrimaryReasoning != "" {
+		sb.WriteString(fmt.Sprintf("💡 %s\n", data.PrimaryReasoning))
+	}
+	for _, rule := range data.RulesTriggered {
+		sb.WriteString(fmt.Sprintf("  • %s (%.0f%%)\n", rule.RuleName, rule.Confidence*100))
+	}
+	sb.WriteString("\n")
+
+	// Price and open position
+	price := s.formatPriceLine(data.Symbol) + s.formatPositionLine(&data)
+	if price != "" {
+		sb.WriteString(price + "\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("🕐 %s · %s", event.Timestamp.Format("2006-01-02 15:04:05 MST"), s.sessionLabel(event.Timestamp)))
+
+	return sb.String()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+func TestDetectFlip(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		window  int
+		symbols map[string]int
+		last    lastSignal // alerted for AAPL
+		symbol  string
+		signal  string
+		want    bool
+	}{
+		{"reversal inside the window", 60, nil, lastSignal{Signal: "BUY", AlertedAt: now.Add(-59 * time.Minute)}, "AAPL", "SELL", true},
+		{"reversal just past the window", 60, nil, lastSignal{Signal: "BUY", AlertedAt: now.Add(-61 * time.Minute)}, "AAPL", "SELL", false},
+		{"same signal", 60, nil, lastSignal{Signal: "SELL", AlertedAt: now}, "AAPL", "SELL", false},
+		{"WATCH never reverses", 60, nil, lastSignal{Signal: "BUY", AlertedAt: now}, "AAPL", "WATCH", false},
+		{"off by default", 0, nil, lastSignal{Signal: "BUY", AlertedAt: now}, "AAPL", "SELL", false},
+		{"symbol override enables", 0, map[string]int{"AAPL": 30}, lastSignal{Signal: "BUY", AlertedAt: now.Add(-29 * time.Minute)}, "AAPL", "SELL", true},
+		{"symbol override shortens", 240, map[string]int{"AAPL": 30}, lastSignal{Signal: "BUY", AlertedAt: now.Add(-31 * time.Minute)}, "AAPL", "SELL", false},
+		{"symbol override disables", 240, map[string]int{"AAPL": 0}, lastSignal{Signal: "BUY", AlertedAt: now}, "AAPL", "SELL", false},
+		{"lower-case symbol", 240, map[string]int{"AAPL": 30}, lastSignal{Signal: "BUY", AlertedAt: now}, " aapl", "SELL", true},
+		{"other symbol", 240, nil, lastSignal{Signal: "BUY", AlertedAt: now}, "MSFT", "SELL", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AlertService{
+				config:  &config.Config{FlipWindowMinutes: tt.window, FlipSymbolMinutes: tt.symbols},
+				signals: map[string]lastSignal{"AAPL": tt.last},
+			}
+			got := s.detectFlip(&models.DecisionData{Symbol: tt.symbol, Signal: tt.signal})
+			if (got != nil) != tt.want {
+				t.Errorf("detectFlip(%s %s) = %+v, want flip %v", tt.symbol, tt.signal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoteSignal(t *testing.T) {
+	s := &AlertService{
+		config:  &config.Config{FlipWindowMinutes: 60},
+		signals: make(map[string]lastSignal),
+	}
+	note := func(symbol, signal string) {
+		s.noteSignal(&models.DecisionEvent{Timestamp: time.Now(),
+			Data: models.DecisionData{Symbol: symbol, Signal: signal, Confidence: 0.8, PrimaryReasoning: signal + " setup"}})
+	}
+
+	note("aapl", models.SignalBuy)
+	note("AAPL", models.SignalWatch)
+	if last, ok := s.signals["AAPL"]; !ok || last.Signal != models.SignalBuy || last.Reasoning != "BUY setup" {
+		t.Fatalf("last AAPL signal = %+v, want the BUY kept through the WATCH", last)
+	}
+	if flip := s.detectFlip(&models.DecisionData{Symbol: "AAPL", Signal: models.SignalSell}); flip == nil {
+		t.Error("SELL after BUY and WATCH not detected as a reversal")
+	}
+
+	note("AAPL", models.SignalSell)
+	if flip := s.detectFlip(&models.DecisionData{Symbol: "Aapl", Signal: models.SignalSell}); flip != nil {
+		t.Errorf("second SELL reverses %+v", flip)
+	}
+	if len(s.signals) != 1 {
+		t.Errorf("signals kept under %d keys, want one per symbol", len(s.signals))
+	}
+}
+
+func TestFormatFlipMessage(t *testing.T) {
+	s, _ := newTestService(t, nil, nil)
+	at := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	prev := &lastSignal{Signal: models.SignalBuy, Confidence: 0.8, Reasoning: "RSI oversold",
+		EventTime: at.Add(-2 * time.Hour), AlertedAt: time.Now().Add(-2 * time.Hour)}
+	event := &models.DecisionEvent{Timestamp: at, Data: models.DecisionData{
+		Symbol: "AAPL", Signal: models.SignalSell, Confidence: 0.65, PrimaryReasoning: "Breakdown below support",
+		RulesTriggered: []models.RuleResult{{RuleName: "support_break", Confidence: 0.7}},
+	}}
+
+	message := s.formatFlipMessage(event, prev)
+	for _, want := range []string{
+		"🔄 <b>SIGNAL REVERSED: AAPL</b>",
+		"🟢 BUY → 🔴 SELL after 2h",
+		"⏮ <b>Before:</b> BUY 80% · 13:00 UTC\n💡 RSI oversold",
+		"⏭ <b>Now:</b> SELL 65%",
+		"💡 Breakdown below support\n  • support_break (70%)",
+		"🕐 2026-03-02 15:00:00 UTC",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("reversal message is missing %q:\n%s", want, message)
+		}
+	}
+}
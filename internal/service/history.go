@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// suppress records an alert that was not sent and why
+func (s *AlertService) suppress(ctx context.Context, record *storage.AlertRecord, reason string) {
+	record.Suppress(reason)
+	s.recordAlert(ctx, record)
+}
+
+// recordAlert writes a processed event to the alert history. Failures are
+// logged rather than returned so the audit trail never blocks delivery.
+func (s *AlertService) recordAlert(ctx context.Context, record *storage.AlertRecord) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.RecordAlert(ctx, record); err != nil {
+		log.Printf("Failed to record alert history for %s %s: %v", record.Kind, record.Symbol, err)
+	}
+}
+
+// isMuted reports whether alerts for the symbol are currently muted.
+// Lookup failures are logged and treated as not muted.
+func (s *AlertService) isMuted(ctx context.Context, symbol string) bool {
+	if s.store == nil {
+		return false
+	}
+	mute, err := s.store.GetMute(ctx, symbol, time.Now())
+	if err != nil {
+		log.Printf("Failed to check mute for %s: %v", symbol, err)
+		return false
+	}
+	return mute != nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// hitRateDefaultDays is the lookback of hit-rate reports unless given
+const hitRateDefaultDays = 30
+
+// HitRateStats summarizes the signals with a return at one horizon. A hit
+// is a price move in the signal's direction: up after BUY, down after SELL.
+type HitRateStats struct {
+	Signals   int     `json:"signals"`
+	Hits      int     `json:"hits"`
+	HitRate   float64 `json:"hit_rate"`
+	AvgReturn float64 `json:"avg_return"` // mean return in the signal's direction
+}
+
+// HitRateGroup is the hit rate of the signals sharing a rule or a
+// confidence bucket
+type HitRateGroup struct {
+	Name     string                  `json:"name"`
+	Signals  int                     `json:"signals"`
+	Horizons map[string]HitRateStats `json:"horizons"`
+}
+
+// HitRateReport is the hit rate of tracked signals by rule and by
+// confidence bucket
+type HitRateReport struct {
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	AlertedOnly  bool           `json:"alerted_only"`
+	Signals      int            `json:"signals"`
+	Overall      HitRateGroup   `json:"overall"`
+	ByRule       []HitRateGroup `json:"by_rule"`
+	ByConfidence []HitRateGroup `json:"by_confidence"`
+}
+
+// HitRates reports the hit rate of the signals tracked in [from, to).
+// Suppressed signals are included unless alertedOnly is set, so the
+// buckets below MinConfidence show what a lower threshold would send.
+func (s *AlertService) HitRates(ctx context.Context, from, to time.Time, alertedOnly bool) (*HitRateReport, error) {
+	if s.outcomes == nil {
+		return nil, fmt.Errorf("outcome tracking needs a storage backend and TRACK_OUTCOMES")
+	}
+
+	outcomes, err := s.store.ListOutcomes(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	overall := &HitRateGroup{Name: "all", Horizons: make(map[string]HitRateStats)}
+	byRule := make(map[string]*HitRateGroup)
+	byConfidence := make(map[string]*HitRateGroup)
+	group := func(groups map[string]*HitRateGroup, name string) *HitRateGroup {
+		if groups[name] == nil {
+			groups[name] = &HitRateGroup{Name: name, Horizons: make(map[string]HitRateStats)}
+		}
+		return groups[name]
+	}
+
+	signals := 0
+	for _, o := range outcomes {
+		if alertedOnly && !o.Alerted {
+			continue
+		}
+		signals++
+		overall.add(o)
+		group(byConfidence, confidenceBucket(o.Confidence)).add(o)
+		seen := make(map[string]bool)
+		for _, rule := range o.Rules {
+			if !seen[rule] {
+				seen[rule] = true
+				group(byRule, rule).add(o)
+			}
+		}
+	}
+
+	report := &HitRateReport{
+		From:         from,
+		To:           to,
+		AlertedOnly:  alertedOnly,
+		Signals:      signals,
+		Overall:      overall.finish(),
+		ByRule:       sortedGroups(byRule),
+		ByConfidence: sortedGroups(byConfidence),
+	}
+	// Highest confidence first
+	sort.Slice(report.ByConfidence, func(i, j int) bool {
+		return report.ByConfidence[i].Name > report.ByConfidence[j].Name
+	})
+	return report, nil
+}
+
+// add counts an outcome at every horizon it has a return for
+func (g *HitRateGroup) add(o storage.Outcome) {
+	g.Signals++
+	for h, ret := range o.Returns {
+		if o.Signal == models.SignalSell {
+			ret = -ret
+		}
+		stats := g.Horizons[h]
+		stats.Signals++
+		if ret > 0 {
+			stats.Hits++
+		}
+		stats.AvgReturn += ret // summed until finish
+		g.Horizons[h] = stats
+	}
+}
+
+// finish turns the summed returns into rates and averages
+func (g *HitRateGroup) finish() HitRateGroup {
+	for h, stats := range g.Horizons {
+		if stats.Signals > 0 {
+			stats.HitRate = float64(stats.Hits) / float64(stats.Signals)
+			stats.AvgReturn /= float64(stats.Signals)
+		}
+		g.Horizons[h] = stats
+	}
+	return *g
+}
+
+// sortedGroups returns the groups with the most signals first
+func sortedGroups(groups map[string]*HitRateGroup) []HitRateGroup {
+	list := make([]HitRateGroup, 0, len(groups))
+	for _, g := range groups {
+		list = append(list, g.finish())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Signals != list[j].Signals {
+			return list[i].Signals > list[j].Signals
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// confidenceBucket names the 0.1-wide confidence range, e.g. "0.70-0.80"
+func confidenceBucket(confidence float64) string {
+	low := math.Floor(confidence*10) / 10
+	if low >= 1 {
+		low = 0.9
+	}
+	if low < 0 {
+		low = 0
+	}
+	return fmt.Sprintf("%.2f-%.2f", low, low+0.1)
+}
+
+// HitRateCommand answers /hitrate [days] with hit rates by confidence
+// bucket and rule
+func (s *AlertService) HitRateCommand(ctx context.Context, chatID int64, args []string) (string, error) {
+	days := hitRateDefaultDays
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return "Usage: /hitrate [days]", nil
+		}
+		days = n
+	}
+
+	now := time.Now()
+	report, err := s.HitRates(ctx, now.AddDate(0, 0, -days), now, false)
+	if err != nil {
+		return "", err
+	}
+	return s.formatHitRates(report, days), nil
+}
+
+func (s *AlertService) formatHitRates(report *HitRateReport, days int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🎯 <b>Signal Hit Rates</b>\n<i>Last %d day(s), %d BUY/SELL signal(s)</i>\n", days, report.Signals))
+	if report.Signals == 0 {
+		sb.WriteString("\nNo tracked signals yet.")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("\n<b>All:</b> %s\n", formatHitRateHorizons(report.Overall)))
+
+	sb.WriteString(fmt.Sprintf("\n<b>By confidence</b> (alerts from %.2f)\n", s.config.MinConfidence))
+	for _, g := range report.ByConfidence {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", g.Name, formatHitRateHorizons(g)))
+	}
+
+	if len(report.ByRule) > 0 {
+		sb.WriteString("\n<b>By rule</b>\n")
+		for _, g := range report.ByRule {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", html.EscapeString(g.Name), formatHitRateHorizons(g)))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatHitRateHorizons renders "1h 60% (+0.4%) · 1d 55% (+1.2%) · 5d – · n=12";
+// horizons without returns yet show a dash
+func formatHitRateHorizons(g HitRateGroup) string {
+	parts := make([]string, 0, len(storage.Horizons))
+	for _, h := range storage.Horizons {
+		stats, ok := g.Horizons[h]
+		if !ok || stats.Signals == 0 {
+			parts = append(parts, h+" –")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %.0f%% (%+.1f%%)", h, stats.HitRate*100, stats.AvgReturn*100))
+	}
+	return fmt.Sprintf("%s · n=%d", strings.Join(parts, " · "), g.Signals)
+}
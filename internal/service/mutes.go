@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// defaultMuteDuration applies to /mute without a duration
+const defaultMuteDuration = 24 * time.Hour
+
+const muteUsage = "Usage: /mute &lt;symbol&gt; [duration] [reason], e.g. /mute TSLA 2d earnings"
+
+// Mutes returns the active symbol mutes
+func (s *AlertService) Mutes(ctx context.Context) ([]storage.Mute, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("mutes need a storage backend")
+	}
+	return s.store.ListMutes(ctx, time.Now())
+}
+
+// MuteCommand answers /mute: without arguments it lists the active mutes,
+// otherwise it silences a symbol's alerts for a duration such as 90m, 4h
+// or 2d (default 24h)
+func (s *AlertService) MuteCommand(ctx context.Context, chatID int64, args []string) (string, error) {
+	if s.store == nil {
+		return "", fmt.Errorf("mutes need a storage backend")
+	}
+	if len(args) == 0 {
+		mutes, err := s.Mutes(ctx)
+		if err != nil {
+			return "", err
+		}
+		return s.formatMutes(mutes), nil
+	}
+
+	mute := storage.Mute{Symbol: strings.ToUpper(args[0]), CreatedAt: time.Now()}
+	duration := defaultMuteDuration
+	reason := args[1:]
+	if len(reason) > 0 {
+		if d, ok := parseMuteDuration(reason[0]); ok {
+			duration, reason = d, reason[1:]
+		}
+	}
+	mute.Until = mute.CreatedAt.Add(duration)
+	mute.Reason = strings.Join(reason, " ")
+
+	if err := s.store.SaveMute(ctx, mute); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🔇 Muted %s", s.formatMute(mute)), nil
+}
+
+// UnmuteCommand answers /unmute <symbol>
+func (s *AlertService) UnmuteCommand(ctx context.Context, chatID int64, args []string) (string, error) {
+	if s.store == nil {
+		return "", fmt.Errorf("mutes need a storage backend")
+	}
+	if len(args) != 1 {
+		return "Usage: /unmute &lt;symbol&gt;", nil
+	}
+
+	symbol := strings.ToUpper(args[0])
+	if err := s.store.DeleteMute(ctx, symbol); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🔔 Unmuted <b>%s</b>.", html.EscapeString(symbol)), nil
+}
+
+// parseMuteDuration reads a positive duration such as 90m, 4h or 2d
+func parseMuteDuration(value string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(strings.ToLower(value), "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+func (s *AlertService) formatMutes(mutes []storage.Mute) string {
+	if len(mutes) == 0 {
+		return "No symbols muted. " + muteUsage
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🔇 <b>Muted Symbols</b>\n")
+	for _, m := range mutes {
+		sb.WriteString("  • " + s.formatMute(m) + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatMute renders "<b>TSLA</b> until Oct 18 16:00 EDT (earnings)"
+func (s *AlertService) formatMute(m storage.Mute) string {
+	line := fmt.Sprintf("<b>%s</b> until %s", html.EscapeString(m.Symbol),
+		m.Until.In(s.quiet.location()).Format("Jan 2 15:04 MST"))
+	if m.Reason != "" {
+		line += fmt.Sprintf(" (%s)", html.EscapeString(m.Reason))
+	}
+	return line
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/calendar"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// outcomePendingWindow bounds how far back incomplete outcomes are
+// restored on startup, so symbols that stopped quoting are dropped
+const outcomePendingWindow = 30 * 24 * time.Hour
+
+// entryPriceKeys are decision metadata keys that may carry the price at
+// decision time, used when no fresh quote is held
+var entryPriceKeys = []string{"price", "current_price", "last_price", "close"}
+
+// outcomeTracker holds the outcomes still waiting for a horizon to pass
+type outcomeTracker struct {
+	mu      sync.Mutex
+	pending map[string][]*storage.Outcome // symbol -> incomplete outcomes
+}
+
+func (t *outcomeTracker) add(o *storage.Outcome) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	symbol := strings.ToUpper(o.Symbol)
+	t.pending[symbol] = append(t.pending[symbol], o)
+}
+
+// update fills in the returns of every horizon that has passed at the
+// quote's time and returns the outcomes that changed
+func (t *outcomeTracker) update(quote models.QuoteData) []storage.Outcome {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	symbol := strings.ToUpper(quote.Symbol)
+	var changed []storage.Outcome
+	kept := t.pending[symbol][:0]
+	for _, o := range t.pending[symbol] {
+		updated := false
+		for _, h := range storage.Horizons {
+			if _, done := o.Returns[h]; done || quote.Timestamp.Before(horizonDue(o.AlertedAt, h)) {
+				continue
+			}
+			o.Returns[h] = (quote.Price - o.EntryPrice) / o.EntryPrice
+			updated = true
+		}
+		if updated {
+			changed = append(changed, *o)
+		}
+		if !o.Complete() {
+			kept = append(kept, o)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(t.pending, symbol)
+	} else {
+		t.pending[symbol] = kept
+	}
+	return changed
+}
+
+// horizonDue returns when a horizon ends for a signal at t. Day horizons
+// count trading days.
+func horizonDue(t time.Time, horizon string) time.Time {
+	switch horizon {
+	case storage.Horizon1h:
+		return t.Add(time.Hour)
+	case storage.Horizon1d:
+		return calendar.NYSE().AddTradingDays(t, 1)
+	default:
+		return calendar.NYSE().AddTradingDays(t, 5)
+	}
+}
+
+// trackOutcome starts measuring the forward returns of a BUY or SELL
+// decision, whether or not it was alerted. It needs a storage backend and
+// a price at decision time.
+func (s *AlertService) trackOutcome(ctx context.Context, event *models.DecisionEvent, record *storage.AlertRecord) {
+	data := event.Data
+	if s.outcomes == nil || record.ID == 0 {
+		return
+	}
+	if data.Signal != models.SignalBuy && data.Signal != models.SignalSell {
+		return
+	}
+
+	price, ok := s.entryPrice(&data)
+	if !ok {
+		return
+	}
+
+	alertedAt := event.Timestamp
+	if alertedAt.IsZero() {
+		alertedAt = record.CreatedAt
+	}
+	rules := make([]string, 0, len(data.RulesTriggered))
+	for _, rule := range data.RulesTriggered {
+		rules = append(rules, rule.RuleName)
+	}
+
+	outcome := &storage.Outcome{
+		RecordID:   record.ID,
+		Symbol:     data.Symbol,
+		Signal:     data.Signal,
+		Confidence: data.Confidence,
+		Rules:      rules,
+		Alerted:    record.Status != storage.StatusSuppressed && record.Status != storage.StatusFailed,
+		EntryPrice: price,
+		AlertedAt:  alertedAt,
+		Returns:    make(map[string]float64),
+	}
+	if err := s.store.SaveOutcome(ctx, outcome); err != nil {
+		log.Printf("Failed to track outcome for %s: %v", data.Symbol, err)
+		return
+	}
+	s.outcomes.add(outcome)
+}
+
+// entryPrice returns the price at decision time: the latest fresh quote,
+// or a price carried in the decision metadata
+func (s *AlertService) entryPrice(data *models.DecisionData) (float64, bool) {
+	if s.market != nil {
+		if snap, ok := s.market.GetFresh(data.Symbol); ok && snap.Price > 0 {
+			return snap.Price, true
+		}
+	}
+
+	return metadataFloat(data, entryPriceKeys...)
+}
+
+// metadataFloat returns the first positive number found in the decision
+// metadata under one of the keys. Numbers sent as strings are accepted.
+func metadataFloat(data *models.DecisionData, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		var value float64
+		switch v := data.Metadata[key].(type) {
+		case float64:
+			value = v
+		case string:
+			value, _ = strconv.ParseFloat(v, 64)
+		}
+		if value > 0 {
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+// updateOutcomes records the returns of tracked signals whose horizons
+// have passed at the quote's time
+func (s *AlertService) updateOutcomes(ctx context.Context, quote models.QuoteData) {
+	if s.outcomes == nil || quote.Price <= 0 {
+		return
+	}
+	for _, outcome := range s.outcomes.update(quote) {
+		if err := s.store.SaveOutcome(ctx, &outcome); err != nil {
+			log.Printf("Failed to save outcome for %s: %v", outcome.Symbol, err)
+		}
+	}
+}
+
+// loadOutcomes restores the outcomes still waiting for a horizon
+func (s *AlertService) loadOutcomes() {
+	if s.outcomes == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	outcomes, err := s.store.ListPendingOutcomes(ctx, time.Now().Add(-outcomePendingWindow))
+	if err != nil {
+		log.Printf("Failed to load pending outcomes: %v", err)
+		return
+	}
+	for i := range outcomes {
+		s.outcomes.add(&outcomes[i])
+	}
+	log.Printf("Tracking %d pending signal outcome(s)", len(outcomes))
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/market"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+	"github.com/trogers1052/alert-service/internal/storage/sqlite"
+)
+
+func TestHorizonDue(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 11, day, hour, minute, 0, 0, loc) }
+
+	tests := []struct {
+		name    string
+		alerted time.Time
+		horizon string
+		want    time.Time
+	}{
+		{"1h is wall-clock", at(24, 15, 30), storage.Horizon1h, at(24, 16, 30)},
+		{"1h over the close", at(27, 19, 45), storage.Horizon1h, at(27, 20, 45)},
+		{"1d is the next trading day", at(24, 15, 30), storage.Horizon1d, at(25, 15, 30)},
+		{"1d skips a holiday", at(25, 10, 0), storage.Horizon1d, at(27, 10, 0)},
+		{"1d skips a weekend", at(27, 12, 0), storage.Horizon1d, time.Date(2026, 11, 30, 12, 0, 0, 0, loc)},
+		{"5d skips a holiday and a weekend", at(23, 9, 45), storage.Horizon5d, time.Date(2026, 12, 1, 9, 45, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := horizonDue(tt.alerted, tt.horizon); !got.Equal(tt.want) {
+				t.Errorf("horizonDue(%s) = %s, want %s", tt.horizon, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutcomeTrackerUpdate(t *testing.T) {
+	alerted := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC) // Monday 10:00 in New York
+	tracker := &outcomeTracker{pending: make(map[string][]*storage.Outcome)}
+	tracker.add(&storage.Outcome{RecordID: 1, Symbol: "aapl", Signal: "BUY", EntryPrice: 100, AlertedAt: alerted,
+		Returns: make(map[string]float64)})
+
+	steps := []struct {
+		after   time.Duration
+		price   float64
+		changed string // horizons filled in by the quote
+		pending bool
+	}{
+		{30 * time.Minute, 101, "", true},
+		{time.Hour, 102, "1h", true},
+		{2 * time.Hour, 90, "", true},
+		{24 * time.Hour, 105, "1d", true},
+		{7 * 24 * time.Hour, 110, "5d", false},
+		{8 * 24 * time.Hour, 120, "", false},
+	}
+
+	for _, step := range steps {
+		changed := tracker.update(models.QuoteData{Symbol: "AAPL", Price: step.price, Timestamp: alerted.Add(step.after)})
+		var horizons []string
+		for _, o := range changed {
+			for _, h := range storage.Horizons {
+				if ret, ok := o.Returns[h]; ok && ret == (step.price-100)/100 {
+					horizons = append(horizons, h)
+				}
+			}
+		}
+		if got := strings.Join(horizons, ","); got != step.changed {
+			t.Errorf("quote after %s changed %q, want %q", step.after, got, step.changed)
+		}
+		if _, pending := tracker.pending["AAPL"]; pending != step.pending {
+			t.Errorf("after %s: pending = %v, want %v", step.after, pending, step.pending)
+		}
+	}
+}
+
+func TestEntryPrice(t *testing.T) {
+	state := market.NewState(time.Minute)
+	state.UpdateQuote(models.QuoteData{Symbol: "AAPL", Price: 187.5, Timestamp: time.Now()})
+	state.UpdateQuote(models.QuoteData{Symbol: "MSFT", Price: 410, Timestamp: time.Now().Add(-time.Hour)})
+	s := &AlertService{market: state}
+
+	tests := []struct {
+		name     string
+		symbol   string
+		metadata map[string]interface{}
+		want     float64
+		ok       bool
+	}{
+		{"fresh quote wins", "AAPL", map[string]interface{}{"price": 180.0}, 187.5, true},
+		{"stale quote falls back to metadata", "MSFT", map[string]interface{}{"price": 405.0}, 405, true},
+		{"metadata string", "TSLA", map[string]interface{}{"current_price": "251.25"}, 251.25, true},
+		{"first positive key", "TSLA", map[string]interface{}{"price": 0.0, "last_price": -1.0, "close": 248.0}, 248, true},
+		{"no price", "TSLA", map[string]interface{}{"price": "n/a"}, 0, false},
+		{"stale quote without metadata", "MSFT", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := s.entryPrice(&models.DecisionData{Symbol: tt.symbol, Metadata: tt.metadata})
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("entryPrice() = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestConfidenceBucket(t *testing.T) {
+	tests := map[float64]string{
+		0:     "0.00-0.10",
+		0.05:  "0.00-0.10",
+		0.299: "0.20-0.30",
+		0.3:   "0.30-0.40",
+		0.699: "0.60-0.70",
+		0.7:   "0.70-0.80",
+		0.75:  "0.70-0.80",
+		0.9:   "0.90-1.00",
+		1:     "0.90-1.00",
+		1.2:   "0.90-1.00",
+		-0.1:  "0.00-0.10",
+	}
+	for confidence, want := range tests {
+		if got := confidenceBucket(confidence); got != want {
+			t.Errorf("confidenceBucket(%v) = %s, want %s", confidence, got, want)
+		}
+	}
+}
+
+func TestHitRates(t *testing.T) {
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "alerts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	for i, o := range []storage.Outcome{
+		// A BUY that rose and a SELL that fell are both hits
+		{Signal: "BUY", Confidence: 0.72, Rules: []string{"rsi_oversold", "macd_cross"}, Alerted: true,
+			Returns: map[string]float64{"1h": 0.02, "1d": 0.04}},
+		{Signal: "SELL", Confidence: 0.78, Rules: []string{"rsi_oversold", "rsi_oversold"}, Alerted: true,
+			Returns: map[string]float64{"1h": -0.01}},
+		{Signal: "BUY", Confidence: 0.65, Rules: []string{"macd_cross"},
+			Returns: map[string]float64{"1h": -0.03}},
+		// Outside the range
+		{Signal: "BUY", Confidence: 0.9, Rules: []string{"rsi_oversold"}, Alerted: true, AlertedAt: at.AddDate(0, 0, -40),
+			Returns: map[string]float64{"1h": 0.5}},
+	} {
+		o.RecordID = int64(i + 1)
+		o.Symbol = "AAPL"
+		o.EntryPrice = 100
+		if o.AlertedAt.IsZero() {
+			o.AlertedAt = at.Add(time.Duration(i) * time.Minute)
+		}
+		if err := store.SaveOutcome(ctx, &o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &AlertService{store: store, outcomes: &outcomeTracker{pending: make(map[string][]*storage.Outcome)}}
+	from, to := at.AddDate(0, 0, -30), at.Add(time.Hour)
+
+	report, err := s.HitRates(ctx, from, to, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Signals != 3 {
+		t.Fatalf("Signals = %d, want 3", report.Signals)
+	}
+	overall := report.Overall.Horizons["1h"]
+	if overall.Signals != 3 || overall.Hits != 2 || !approx(overall.AvgReturn, 0) {
+		t.Errorf("overall 1h = %+v, want 2 of 3 hits averaging 0", overall)
+	}
+
+	groups := func(list []HitRateGroup) string {
+		names := make([]string, len(list))
+		for i, g := range list {
+			names[i] = g.Name
+		}
+		return strings.Join(names, ",")
+	}
+	if got := groups(report.ByConfidence); got != "0.70-0.80,0.60-0.70" {
+		t.Errorf("confidence buckets = %s, want 0.70-0.80,0.60-0.70", got)
+	}
+	if got := groups(report.ByRule); got != "macd_cross,rsi_oversold" {
+		t.Errorf("rules = %s, want macd_cross,rsi_oversold", got)
+	}
+	for _, g := range report.ByRule {
+		stats := g.Horizons["1h"]
+		switch g.Name {
+		case "rsi_oversold":
+			// A rule listed twice counts its signal once
+			if g.Signals != 2 || stats.Hits != 2 || stats.HitRate != 1 || !approx(stats.AvgReturn, 0.015) {
+				t.Errorf("rsi_oversold = %+v", g)
+			}
+			if d := g.Horizons["1d"]; d.Signals != 1 || d.HitRate != 1 {
+				t.Errorf("rsi_oversold 1d = %+v, want one hit", d)
+			}
+		case "macd_cross":
+			if g.Signals != 2 || stats.Hits != 1 || stats.HitRate != 0.5 || !approx(stats.AvgReturn, -0.005) {
+				t.Errorf("macd_cross = %+v", g)
+			}
+		}
+	}
+
+	alerted, err := s.HitRates(ctx, from, to, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerted.Signals != 2 || groups(alerted.ByConfidence) != "0.70-0.80" {
+		t.Errorf("alerted only: %d signals in %s, want 2 in 0.70-0.80", alerted.Signals, groups(alerted.ByConfidence))
+	}
+}
+
+func approx(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/portfolio"
+)
+
+// SetPositions enables position awareness: SELL filtering, scale-in
+// detection from holdings and position lines in decision alerts
+func (s *AlertService) SetPositions(book *portfolio.Book) {
+	s.positions = book
+}
+
+// HandlePositionEvent applies position updates from the positions topic
+func (s *AlertService) HandlePositionEvent(ctx context.Context, event interface{}) error {
+	update, ok := event.(*models.PositionEvent)
+	if !ok {
+		return fmt.Errorf("invalid event type for position handler")
+	}
+	if s.positions == nil {
+		return nil
+	}
+
+	positions := make([]portfolio.Position, 0, len(update.Data.Positions))
+	for _, p := range update.Data.Positions {
+		positions = append(positions, portfolio.Position{
+			Symbol:    p.Symbol,
+			Quantity:  p.Quantity,
+			CostBasis: p.CostBasis,
+			UpdatedAt: update.Timestamp,
+		})
+	}
+
+	if update.Data.Snapshot {
+		s.positions.Replace(positions)
+		log.Printf("Replaced positions with a snapshot of %d position(s)", len(positions))
+		return nil
+	}
+	for _, p := range positions {
+		s.positions.Update(p)
+	}
+	return nil
+}
+
+// heldPosition returns the open position in a symbol. known is false when
+// positions are not configured or not loaded yet, in which case callers
+// fall back to their behavior without positions.
+func (s *AlertService) heldPosition(symbol string) (position portfolio.Position, held, known bool) {
+	if s.positions == nil || !s.positions.Loaded() {
+		return position, false, false
+	}
+	position, held = s.positions.Get(symbol)
+	return position, held, true
+}
+
+// isHeldForSell reports whether a decision passes the SELL filter: SELLs
+// are only alerted for symbols with an open position
+func (s *AlertService) isHeldForSell(data *models.DecisionData) bool {
+	if !s.config.PositionsFilterSells || data.Signal != models.SignalSell {
+		return true
+	}
+	_, held, known := s.heldPosition(data.Symbol)
+	return held || !known
+}
+
+// formatPositionLine renders the open position in the decision's symbol,
+// e.g. "💼 Position: 10 sh @ $150.00 · value $1520.00 · P&L +$20.00 (+1.3%)"
+func (s *AlertService) formatPositionLine(data *models.DecisionData) string {
+	position, held, _ := s.heldPosition(data.Symbol)
+	if !held {
+		return ""
+	}
+
+	line := fmt.Sprintf("💼 Position: %s sh @ $%.2f", formatQuantity(position.Quantity), position.CostBasis)
+	price, ok := s.entryPrice(data)
+	if !ok {
+		return line + fmt.Sprintf(" · cost $%.2f\n", position.Cost())
+	}
+	pnl, pct := position.UnrealizedPnL(price)
+	return line + fmt.Sprintf(" · value $%.2f · P&L %s (%+.1f%%)\n", position.Quantity*price, formatSignedDollars(pnl), pct*100)
+}
+
+// formatQuantity renders a share count without trailing zeros, so
+// fractional shares show as "2.5"
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
+
+// formatSignedDollars renders an amount as "+$20.00" or "-$20.00"
+func formatSignedDollars(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f", math.Abs(v))
+	}
+	return fmt.Sprintf("+$%.2f", v)
+}
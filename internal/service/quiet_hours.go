@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/schedule"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// quietHours resolves the quiet schedule of every recipient. Subscribers
+// with their own quiet hours use them; everyone else uses the global schedule.
+type quietHours struct {
+	global      *schedule.Schedule // nil when quiet hours are disabled
+	subscribers storage.SubscriberStore
+	chatID      int64 // configured chat, the only recipient without a store
+
+	mu    sync.Mutex
+	cache map[string]*schedule.Schedule // parsed subscriber schedules by zone and spec
+}
+
+func newQuietHours(cfg *config.Config, store storage.Store) *quietHours {
+	q := &quietHours{
+		chatID: cfg.TelegramChatID,
+		cache:  make(map[string]*schedule.Schedule),
+	}
+	if store != nil {
+		q.subscribers = store
+	}
+
+	// The config was validated on load
+	if cfg.QuietHours != "" {
+		loc, err := schedule.LoadLocation(cfg.QuietHoursTimezone)
+		if err == nil {
+			q.global, err = schedule.Parse(cfg.QuietHours, loc)
+		}
+		if err != nil {
+			log.Printf("Warning: quiet hours disabled: %v", err)
+		}
+	}
+	return q
+}
+
+// scheduleFor returns the subscriber's schedule, falling back to the global
+// schedule when the subscriber has none or it does not parse
+func (q *quietHours) scheduleFor(sub storage.Subscriber) *schedule.Schedule {
+	if sub.QuietHours == "" {
+		return q.global
+	}
+
+	key := sub.Timezone + "|" + sub.QuietHours
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if sched, ok := q.cache[key]; ok {
+		return sched
+	}
+
+	loc, err := schedule.LoadLocation(sub.Timezone)
+	if err == nil {
+		var sched *schedule.Schedule
+		if sched, err = schedule.Parse(sub.QuietHours, loc); err == nil {
+			q.cache[key] = sched
+			return sched
+		}
+	}
+	log.Printf("Invalid quiet hours for chat %d, using global schedule: %v", sub.ChatID, err)
+	q.cache[key] = q.global
+	return q.global
+}
+
+// location returns the zone of the global schedule, or UTC without one
+func (q *quietHours) location() *time.Location {
+	if q.global == nil {
+		return time.UTC
+	}
+	return q.global.Location()
+}
+
+// Partition splits the enabled chats into those outside and those inside
+// quiet hours at now
+func (q *quietHours) Partition(ctx context.Context, now time.Time) (active, quiet []int64, err error) {
+	if q.subscribers == nil {
+		if q.global.Quiet(now) {
+			return nil, []int64{q.chatID}, nil
+		}
+		return []int64{q.chatID}, nil, nil
+	}
+
+	subscribers, err := q.subscribers.ListSubscribers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	active = []int64{}
+	for _, sub := range subscribers {
+		switch {
+		case !sub.Enabled:
+		case q.scheduleFor(sub).Quiet(now):
+			quiet = append(quiet, sub.ChatID)
+		default:
+			active = append(active, sub.ChatID)
+		}
+	}
+	return active, quiet, nil
+}
+
+// allQuiet reports whether everyone is in quiet hours given a partition at
+// now. With no enabled subscribers the global schedule decides, so channels
+// other than Telegram still get alerts outside quiet hours.
+func (q *quietHours) allQuiet(active, quiet []int64, now time.Time) bool {
+	if len(active) == 0 && len(quiet) == 0 {
+		return q.global.Quiet(now)
+	}
+	return len(active) == 0
+}
+
+// Recipients returns the chats that are not in quiet hours at now, or every
+// enabled chat when ignoreQuiet is set
+func (q *quietHours) Recipients(ctx context.Context, now time.Time, ignoreQuiet bool) ([]int64, error) {
+	active, quiet, err := q.Partition(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if ignoreQuiet {
+		return append(active, quiet...), nil
+	}
+	return active, nil
+}
+
+// recipientsKey overrides the chats an alert is delivered to, e.g. to send
+// a quiet hours digest to the one chat whose quiet hours ended
+type recipientsKey struct{}
+
+func withRecipients(ctx context.Context, chatIDs []int64) context.Context {
+	return context.WithValue(ctx, recipientsKey{}, chatIDs)
+}
+
+// AlertRecipients returns the chats that should receive an alert right now.
+// Breakthrough alerts go to every enabled subscriber.
+func (s *AlertService) AlertRecipients(ctx context.Context) ([]int64, error) {
+	if ids, ok := ctx.Value(recipientsKey{}).([]int64); ok {
+		return ids, nil
+	}
+	return s.quiet.Recipients(ctx, time.Now(), isBreakthrough(ctx))
+}
+
+// isQuietHours reports whether every recipient is in quiet hours. A failed
+// subscriber lookup falls back to the global schedule.
+func (s *AlertService) isQuietHours(ctx context.Context) bool {
+	now := time.Now()
+	active, quiet, err := s.quiet.Partition(ctx, now)
+	if err != nil {
+		log.Printf("Failed to resolve quiet hours per subscriber: %v", err)
+		return s.quiet.global.Quiet(now)
+	}
+	return s.quiet.allQuiet(active, quiet, now)
+}
+
+// quietChats returns the chats in quiet hours right now. A failed
+// subscriber lookup falls back to the configured chat and global schedule.
+func (s *AlertService) quietChats(ctx context.Context) []int64 {
+	now := time.Now()
+	_, quiet, err := s.quiet.Partition(ctx, now)
+	if err != nil {
+		log.Printf("Failed to resolve quiet hours per subscriber: %v", err)
+		if s.quiet.global.Quiet(now) {
+			return []int64{s.config.TelegramChatID}
+		}
+		return nil
+	}
+	return quiet
+}
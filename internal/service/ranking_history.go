@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// RankPoint is a symbol's position in one ranking
+type RankPoint struct {
+	Time       time.Time `json:"time"`
+	Rank       int       `json:"rank"`
+	Score      float64   `json:"score"`
+	Confidence float64   `json:"confidence"`
+}
+
+// RankTimeline is a symbol's rank and score over time in the rankings of
+// one signal type
+type RankTimeline struct {
+	Symbol     string      `json:"symbol"`
+	SignalType string      `json:"signal_type"`
+	Points     []RankPoint `json:"points"`
+}
+
+// Trend summarizes the timeline as "#7 → #2 over 3 days", or returns ""
+// when the rank did not change
+func (t *RankTimeline) Trend() string {
+	if len(t.Points) < 2 {
+		return ""
+	}
+	first, last := t.Points[0], t.Points[len(t.Points)-1]
+	if first.Rank == last.Rank {
+		return ""
+	}
+	return fmt.Sprintf("#%d → #%d over %s", first.Rank, last.Rank, formatSpan(last.Time.Sub(first.Time)))
+}
+
+// formatSpan renders a duration as whole days, hours or minutes
+func formatSpan(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		days := int((d + 12*time.Hour) / (24 * time.Hour))
+		return fmt.Sprintf("%d day%s", days, plural(days, "", "s"))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Round(time.Hour)/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", int(d.Round(time.Minute)/time.Minute))
+	}
+}
+
+// RankTimeline returns a symbol's rankings of a signal type since the
+// given time, oldest first
+func (s *AlertService) RankTimeline(ctx context.Context, signalType, symbol string, since time.Time) (*RankTimeline, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("ranking history needs a storage backend")
+	}
+
+	entries, err := s.store.SymbolRankings(ctx, signalType, symbol, since)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &RankTimeline{Symbol: symbol, SignalType: signalType, Points: make([]RankPoint, 0, len(entries))}
+	for _, e := range entries {
+		timeline.Symbol = e.Symbol
+		timeline.Points = append(timeline.Points, RankPoint{Time: e.RankedAt, Rank: e.Rank, Score: e.Score, Confidence: e.Confidence})
+	}
+	return timeline, nil
+}
+
+// rankedAt returns the time a ranking was produced
+func rankedAt(event *models.RankingEvent) time.Time {
+	switch {
+	case !event.Data.Timestamp.IsZero():
+		return event.Data.Timestamp
+	case !event.Timestamp.IsZero():
+		return event.Timestamp
+	default:
+		return time.Now()
+	}
+}
+
+// recordRanking persists every entry of a ranking
+func (s *AlertService) recordRanking(ctx context.Context, event *models.RankingEvent) {
+	if s.store == nil {
+		return
+	}
+
+	at := rankedAt(event)
+	entries := make([]storage.RankingEntry, 0, len(event.Data.Rankings))
+	for i, r := range event.Data.Rankings {
+		rank := r.Rank
+		if rank <= 0 {
+			rank = i + 1
+		}
+		entries = append(entries, storage.RankingEntry{
+			SignalType: event.Data.SignalType,
+			Symbol:     r.Symbol,
+			Rank:       rank,
+			Score:      r.Score,
+			Confidence: r.Confidence,
+			RankedAt:   at,
+		})
+	}
+	if err := s.store.SaveRankings(ctx, entries); err != nil {
+		log.Printf("Failed to record %s ranking history: %v", event.Data.SignalType, err)
+	}
+}
+
+// rankTrends returns the rank trend over the timeline window of each
+// symbol shown in the top N, such as "📈 #7 → #2 over 3 days", keyed by
+// symbol
+func (s *AlertService) rankTrends(ctx context.Context, event *models.RankingEvent) map[string]string {
+	trends := make(map[string]string)
+	if s.store == nil || s.config.RankingTimelineDays <= 0 {
+		return trends
+	}
+
+	since := rankedAt(event).AddDate(0, 0, -s.config.RankingTimelineDays)
+	for i, r := range event.Data.Rankings {
+		if i >= s.config.RankingsTopN {
+			break
+		}
+		timeline, err := s.RankTimeline(ctx, event.Data.SignalType, r.Symbol, since)
+		if err != nil {
+			log.Printf("Failed to load rank timeline for %s: %v", r.Symbol, err)
+			return trends
+		}
+		trend := timeline.Trend()
+		if trend == "" {
+			continue
+		}
+		if first, last := timeline.Points[0], timeline.Points[len(timeline.Points)-1]; last.Rank < first.Rank {
+			trends[r.Symbol] = "📈 " + trend
+		} else {
+			trends[r.Symbol] = "📉 " + trend
+		}
+	}
+	return trends
+}
+
+// loadRankings restores the latest ranking of each signal type so the
+// first ranking after a restart is still shown as a diff
+func (s *AlertService) loadRankings() {
+	if s.store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s.ExpireRankings(ctx)
+
+	for _, signalType := range digestSignalOrder {
+		entries, err := s.store.LatestRankings(ctx, signalType)
+		if err != nil {
+			log.Printf("Failed to load latest %s ranking: %v", signalType, err)
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		data := &models.RankingData{SignalType: signalType, Timestamp: entries[0].RankedAt}
+		for _, e := range entries {
+			data.Rankings = append(data.Rankings, models.SymbolRanking{
+				Symbol:     e.Symbol,
+				Rank:       e.Rank,
+				Score:      e.Score,
+				SignalType: signalType,
+				Confidence: e.Confidence,
+			})
+		}
+		s.rankings.swap(data)
+	}
+}
+
+// ExpireRankings deletes ranking history older than the retention period
+func (s *AlertService) ExpireRankings(ctx context.Context) {
+	if s.store == nil || s.config.RankingRetentionDays <= 0 {
+		return
+	}
+
+	n, err := s.store.DeleteRankingsBefore(ctx, time.Now().AddDate(0, 0, -s.config.RankingRetentionDays))
+	if err != nil {
+		log.Printf("Failed to expire ranking history: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("Expired %d ranking history entries", n)
+	}
+}
+
+// RunRankingRetention expires old ranking history every interval until the
+// context is cancelled
+func (s *AlertService) RunRankingRetention(ctx context.Context, interval time.Duration) {
+	if s.store == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.ExpireRankings(ctx)
+		}
+	}
+}
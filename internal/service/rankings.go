@@ -0,0 +1,266 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+// rankingCache holds the latest ranking received for each signal type
+type rankingCache struct {
+	mu     sync.RWMutex
+	latest map[string]*models.RankingData
+}
+
+// swap stores a ranking and returns the one it replaces, or nil
+func (c *rankingCache) swap(data *models.RankingData) *models.RankingData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.latest == nil {
+		c.latest = make(map[string]*models.RankingData)
+	}
+	previous := c.latest[data.SignalType]
+	c.latest[data.SignalType] = data
+	return previous
+}
+
+// get returns the latest ranking for a signal type, or nil
+func (c *rankingCache) get(signalType string) *models.RankingData {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.latest[signalType]
+}
+
+// rankChange is one symbol's position in two consecutive rankings. A rank
+// of 0 means the symbol was not ranked.
+type rankChange struct {
+	Symbol   string
+	OldRank  int
+	NewRank  int
+	OldScore float64
+	NewScore float64
+}
+
+// rankingDiff lists the meaningful changes to the top N between two
+// rankings of the same signal type
+type rankingDiff struct {
+	Entered  []rankChange // new in the top N
+	Dropped  []rankChange // no longer in the top N
+	Moved    []rankChange // in both top Ns, moved more than the configured ranks
+	Rescored []rankChange // in both top Ns, score changed by at least the threshold
+}
+
+func (d *rankingDiff) empty() bool {
+	return len(d.Entered) == 0 && len(d.Dropped) == 0 && len(d.Moved) == 0 && len(d.Rescored) == 0
+}
+
+// rankOf returns the 1-based rank of each ranked symbol
+func rankOf(data *models.RankingData) map[string]models.SymbolRanking {
+	ranks := make(map[string]models.SymbolRanking, len(data.Rankings))
+	for i, r := range data.Rankings {
+		if r.Rank <= 0 {
+			r.Rank = i + 1
+		}
+		if _, dup := ranks[r.Symbol]; !dup {
+			ranks[r.Symbol] = r
+		}
+	}
+	return ranks
+}
+
+// diffRankings compares the top N of two rankings
+func (s *AlertService) diffRankings(previous, current *models.RankingData) *rankingDiff {
+	topN := s.config.RankingsTopN
+	oldRanks, newRanks := rankOf(previous), rankOf(current)
+	inTop := func(r models.SymbolRanking, ok bool) bool {
+		return ok && r.Rank <= topN
+	}
+
+	diff := &rankingDiff{}
+	for symbol, now := range newRanks {
+		before, wasRanked := oldRanks[symbol]
+		change := rankChange{Symbol: symbol, OldRank: before.Rank, NewRank: now.Rank,
+			OldScore: before.Score, NewScore: now.Score}
+
+		switch {
+		case !inTop(now, true):
+			continue
+		case !inTop(before, wasRanked):
+			diff.Entered = append(diff.Entered, change)
+		default:
+			if moved := before.Rank - now.Rank; moved > s.config.RankingsRankMove || -moved > s.config.RankingsRankMove {
+				diff.Moved = append(diff.Moved, change)
+			}
+			if s.config.RankingsScoreChange > 0 && math.Abs(now.Score-before.Score) >= s.config.RankingsScoreChange {
+				diff.Rescored = append(diff.Rescored, change)
+			}
+		}
+	}
+
+	for symbol, before := range oldRanks {
+		now, stillRanked := newRanks[symbol]
+		if inTop(before, true) && !inTop(now, stillRanked) {
+			diff.Dropped = append(diff.Dropped, rankChange{Symbol: symbol, OldRank: before.Rank, NewRank: now.Rank,
+				OldScore: before.Score, NewScore: now.Score})
+		}
+	}
+
+	for _, list := range [][]rankChange{diff.Entered, diff.Moved, diff.Rescored} {
+		sort.Slice(list, func(i, j int) bool { return list[i].NewRank < list[j].NewRank })
+	}
+	sort.Slice(diff.Dropped, func(i, j int) bool { return diff.Dropped[i].OldRank < diff.Dropped[j].OldRank })
+	return diff
+}
+
+// formatRankingDiff renders only what changed in the top N since the
+// previous ranking, followed by the current top N in one line
+func (s *AlertService) formatRankingDiff(event *models.RankingEvent, previous *models.RankingData, diff *rankingDiff, trends map[string]string) string {
+	data := event.Data
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s <b>%s Rankings Update</b>\n", signalEmoji(data.SignalType), data.SignalType))
+	sb.WriteString(fmt.Sprintf("📅 %s <i>(changes since %s)</i>\n", data.Timestamp.Format("2006-01-02 15:04"),
+		previous.Timestamp.Format("01-02 15:04")))
+
+	if diff.empty() {
+		sb.WriteString(fmt.Sprintf("\nNo changes in the top %d.\n", s.config.RankingsTopN))
+	}
+
+	if len(diff.Entered) > 0 {
+		sb.WriteString(fmt.Sprintf("\n🆕 <b>New in top %d</b>\n", s.config.RankingsTopN))
+		for _, c := range diff.Entered {
+			from := "unranked"
+			if c.OldRank > 0 {
+				from = fmt.Sprintf("#%d", c.OldRank)
+			}
+			sb.WriteString(fmt.Sprintf("<b>%s</b> #%d (from %s) · score %.2f%s\n", c.Symbol, c.NewRank, from, c.NewScore,
+				trendSuffix(trends[c.Symbol])))
+		}
+	}
+
+	if len(diff.Dropped) > 0 {
+		sb.WriteString("\n📤 <b>Dropped out</b>\n")
+		for _, c := range diff.Dropped {
+			to := "unranked"
+			if c.NewRank > 0 {
+				to = fmt.Sprintf("#%d", c.NewRank)
+			}
+			sb.WriteString(fmt.Sprintf("<b>%s</b> #%d → %s\n", c.Symbol, c.OldRank, to))
+		}
+	}
+
+	if len(diff.Moved) > 0 {
+		sb.WriteString("\n↕️ <b>Big moves</b>\n")
+		for _, c := range diff.Moved {
+			arrow := "⬆️"
+			if c.NewRank > c.OldRank {
+				arrow = "⬇️"
+			}
+			sb.WriteString(fmt.Sprintf("<b>%s</b> #%d → #%d %s%s\n", c.Symbol, c.OldRank, c.NewRank, arrow,
+				trendSuffix(trends[c.Symbol])))
+		}
+	}
+
+	if len(diff.Rescored) > 0 {
+		sb.WriteString("\n📊 <b>Score changes</b>\n")
+		for _, c := range diff.Rescored {
+			sb.WriteString(fmt.Sprintf("<b>%s</b> %.2f → %.2f (%+.2f)\n", c.Symbol, c.OldScore, c.NewScore, c.NewScore-c.OldScore))
+		}
+	}
+
+	count := s.config.RankingsTopN
+	if count > len(data.Rankings) {
+		count = len(data.Rankings)
+	}
+	top := make([]string, 0, count)
+	for _, r := range data.Rankings[:count] {
+		top = append(top, r.Symbol)
+	}
+	sb.WriteString(fmt.Sprintf("\n<b>Top %d:</b> %s", count, strings.Join(top, ", ")))
+	return sb.String()
+}
+
+// rankingFactorsShown is how many ranking factors decision alerts list
+const rankingFactorsShown = 3
+
+// rankingPosition finds a symbol in the latest ranking of a signal type.
+// ranked is false when there is no ranking of that type yet.
+func (s *AlertService) rankingPosition(signalType, symbol string) (entry models.SymbolRanking, found, ranked bool) {
+	data := s.rankings.get(signalType)
+	if data == nil {
+		return entry, false, false
+	}
+	for i, r := range data.Rankings {
+		if strings.EqualFold(r.Symbol, symbol) {
+			if r.Rank <= 0 {
+				r.Rank = i + 1
+			}
+			return r, true, true
+		}
+	}
+	return entry, false, true
+}
+
+// inRankingTopN reports whether a decision passes the ranking filter: the
+// symbol must be in the top N of the latest ranking of the decision's
+// signal type. Signals without a ranking yet are let through.
+func (s *AlertService) inRankingTopN(data *models.DecisionData) bool {
+	if s.config.RankingFilterTopN <= 0 || !containsString(s.config.RankingFilterSignals, data.Signal) {
+		return true
+	}
+	entry, found, ranked := s.rankingPosition(data.Signal, data.Symbol)
+	if !ranked {
+		return true
+	}
+	return found && entry.Rank <= s.config.RankingFilterTopN
+}
+
+// formatRankingLine cross-references a decision with the latest ranking of
+// its signal type: rank, score and the strongest ranking factors
+func (s *AlertService) formatRankingLine(data *models.DecisionData) string {
+	entry, found, ranked := s.rankingPosition(data.Signal, data.Symbol)
+	if !ranked {
+		return ""
+	}
+	if !found {
+		return fmt.Sprintf("🏆 Not in the latest %s rankings\n", data.Signal)
+	}
+
+	latest := s.rankings.get(data.Signal)
+	line := fmt.Sprintf("🏆 #%d of %d in %s rankings · score %.2f\n", entry.Rank, len(latest.Rankings), data.Signal, entry.Score)
+
+	names := make([]string, 0, len(entry.RankingFactors))
+	for name := range entry.RankingFactors {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := math.Abs(entry.RankingFactors[names[i]]), math.Abs(entry.RankingFactors[names[j]])
+		if a != b {
+			return a > b
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > rankingFactorsShown {
+		names = names[:rankingFactorsShown]
+	}
+	if len(names) > 0 {
+		factors := make([]string, len(names))
+		for i, name := range names {
+			factors[i] = fmt.Sprintf("%s %.2f", name, entry.RankingFactors[name])
+		}
+		line += fmt.Sprintf("    └ %s\n", strings.Join(factors, ", "))
+	}
+	return line
+}
+
+// trendSuffix appends a rank trend to a line when there is one
+func trendSuffix(trend string) string {
+	if trend == "" {
+		return ""
+	}
+	return " · " + trend
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+// ranking builds ranking data from "SYMBOL:score" entries in rank order
+func ranking(t *testing.T, entries ...string) *models.RankingData {
+	t.Helper()
+	data := &models.RankingData{SignalType: models.SignalBuy}
+	for _, entry := range entries {
+		var r models.SymbolRanking
+		symbol, score, _ := strings.Cut(entry, ":")
+		if _, err := fmt.Sscan(score, &r.Score); err != nil {
+			t.Fatalf("bad ranking entry %q: %v", entry, err)
+		}
+		r.Symbol = symbol
+		data.Rankings = append(data.Rankings, r)
+	}
+	return data
+}
+
+// changes renders rank changes as "SYMBOL old→new"
+func changes(list []rankChange) string {
+	out := make([]string, len(list))
+	for i, c := range list {
+		out[i] = fmt.Sprintf("%s %d→%d", c.Symbol, c.OldRank, c.NewRank)
+	}
+	return strings.Join(out, ", ")
+}
+
+// summary renders a diff as "entered ...; dropped ...; moved ...; rescored ..."
+// with empty groups left out
+func summary(d *rankingDiff) string {
+	var parts []string
+	for _, group := range []struct {
+		name string
+		list []rankChange
+	}{{"entered", d.Entered}, {"dropped", d.Dropped}, {"moved", d.Moved}, {"rescored", d.Rescored}} {
+		if len(group.list) > 0 {
+			parts = append(parts, group.name+" "+changes(group.list))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func TestDiffRankings(t *testing.T) {
+	s := &AlertService{config: &config.Config{RankingsTopN: 3, RankingsRankMove: 1, RankingsScoreChange: 0.1}}
+
+	// A day of rankings, each diffed against the one before
+	day := []struct {
+		entries []string
+		want    string
+	}{
+		{[]string{"AAPL:0.9", "MSFT:0.8", "NVDA:0.7", "AMD:0.6"}, ""},
+		{[]string{"AAPL:0.9", "MSFT:0.8", "NVDA:0.7", "AMD:0.6"}, ""},
+		{[]string{"TSLA:0.95", "AMD:0.9", "AAPL:0.85", "MSFT:0.8"},
+			"entered TSLA 0→1, AMD 4→2; dropped MSFT 2→4, NVDA 3→0; moved AAPL 1→3"},
+		// A one-place swap is within the rank threshold
+		{[]string{"AMD:0.95", "TSLA:0.9", "AAPL:0.85", "MSFT:0.8"}, ""},
+		{[]string{"AAPL:0.95", "TSLA:0.9", "AMD:0.85", "MSFT:0.8", "NVDA:0.7"}, "moved AAPL 3→1, AMD 1→3"},
+		{[]string{"AAPL:0.8", "TSLA:0.85", "AMD:0.7", "MSFT:0.8"}, "rescored AAPL 1→1, AMD 3→3"},
+		// Only the best rank of a repeated symbol counts
+		{[]string{"AAPL:0.8", "TSLA:0.85", "AMD:0.7", "MSFT:0.8", "AAPL:0.1"}, ""},
+	}
+
+	previous := ranking(t, day[0].entries...)
+	for i, step := range day[1:] {
+		current := ranking(t, step.entries...)
+		diff := s.diffRankings(previous, current)
+		if got := summary(diff); got != step.want {
+			t.Errorf("step %d: diff = %q, want %q", i+1, got, step.want)
+		}
+		if diff.empty() != (step.want == "") {
+			t.Errorf("step %d: empty() = %v", i+1, diff.empty())
+		}
+		previous = current
+	}
+
+	// Explicit ranks win over the order of the list
+	explicit := func(ranks map[string]int) *models.RankingData {
+		data := &models.RankingData{}
+		for _, symbol := range []string{"AAPL", "MSFT", "NVDA"} {
+			data.Rankings = append(data.Rankings, models.SymbolRanking{Symbol: symbol, Rank: ranks[symbol]})
+		}
+		return data
+	}
+	diff := s.diffRankings(
+		explicit(map[string]int{"AAPL": 1, "MSFT": 2, "NVDA": 3}),
+		explicit(map[string]int{"AAPL": 1, "MSFT": 4, "NVDA": 3}))
+	if got := summary(diff); got != "dropped MSFT 2→4" {
+		t.Errorf("explicit ranks: diff = %q, want MSFT dropped", got)
+	}
+}
+
+func TestRankingFilterAndLine(t *testing.T) {
+	s := &AlertService{config: &config.Config{RankingFilterTopN: 2, RankingFilterSignals: []string{"BUY"}}}
+
+	// No ranking yet lets every decision through without a ranking line
+	if !s.inRankingTopN(&models.DecisionData{Symbol: "AAPL", Signal: "BUY"}) {
+		t.Error("decision filtered before the first ranking")
+	}
+	if line := s.formatRankingLine(&models.DecisionData{Symbol: "AAPL", Signal: "BUY"}); line != "" {
+		t.Errorf("ranking line before the first ranking = %q, want none", line)
+	}
+
+	// Explicit ranks win; entries without one take their position
+	s.rankings.swap(&models.RankingData{SignalType: "BUY", Rankings: []models.SymbolRanking{
+		{Symbol: "MSFT", Score: 0.9},
+		{Symbol: "NVDA", Rank: 3, Score: 0.8},
+		{Symbol: "aapl", Score: 0.7, RankingFactors: map[string]float64{"momentum": 0.4, "value": -0.6, "quality": 0.1, "size": 0.05}},
+	}})
+
+	tests := []struct {
+		name   string
+		data   *models.DecisionData
+		passes bool
+		line   string
+	}{
+		{"in the top N", &models.DecisionData{Symbol: "MSFT", Signal: "BUY"}, true, "🏆 #1 of 3 in BUY rankings · score 0.90\n"},
+		{"explicit rank outside the top N", &models.DecisionData{Symbol: "NVDA", Signal: "BUY"}, false, "🏆 #3 of 3 in BUY rankings · score 0.80\n"},
+		{"position fallback and case-insensitive symbol", &models.DecisionData{Symbol: "AAPL", Signal: "BUY"}, false,
+			"🏆 #3 of 3 in BUY rankings · score 0.70\n    └ value -0.60, momentum 0.40, quality 0.10\n"},
+		{"not ranked", &models.DecisionData{Symbol: "TSLA", Signal: "BUY"}, false, "🏆 Not in the latest BUY rankings\n"},
+		{"signal not filtered", &models.DecisionData{Symbol: "TSLA", Signal: "SELL"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.inRankingTopN(tt.data); got != tt.passes {
+				t.Errorf("inRankingTopN() = %v, want %v", got, tt.passes)
+			}
+			if got := s.formatRankingLine(tt.data); got != tt.line {
+				t.Errorf("formatRankingLine() = %q, want %q", got, tt.line)
+			}
+		})
+	}
+
+	s.config.RankingFilterTopN = 0
+	if !s.inRankingTopN(&models.DecisionData{Symbol: "TSLA", Signal: "BUY"}) {
+		t.Error("decision filtered with the ranking filter off")
+	}
+}
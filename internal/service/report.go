@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/calendar"
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/schedule"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// reportCheckInterval is how often report schedules are checked
+const reportCheckInterval = 30 * time.Second
+
+// report is a scheduled summary of alert history
+type report struct {
+	title      string
+	days       int  // calendar days covered, ending on the report day
+	tradingDay bool // only sent on trading days
+	recurrence *schedule.Recurrence
+}
+
+func newReports(cfg *config.Config) []report {
+	// The config was validated on load
+	loc, err := schedule.LoadLocation(cfg.ReportTimezone)
+	if err != nil {
+		log.Printf("Warning: reports disabled: %v", err)
+		return nil
+	}
+
+	var reports []report
+	for _, r := range []struct {
+		spec string
+		report
+	}{
+		{cfg.ReportEOD, report{title: "End-of-Day Report", days: 1, tradingDay: true}},
+		{cfg.ReportWeekly, report{title: "Weekly Report", days: 7}},
+	} {
+		if r.spec == "" {
+			continue
+		}
+		recurrence, err := schedule.ParseRecurrence(r.spec, loc)
+		if err != nil {
+			log.Printf("Warning: %s disabled: %v", r.title, err)
+			continue
+		}
+		r.report.recurrence = recurrence
+		reports = append(reports, r.report)
+	}
+	return reports
+}
+
+// RunReports sends the end-of-day and weekly reports on their schedules
+// until the context is cancelled. Reports need a storage backend.
+func (s *AlertService) RunReports(ctx context.Context) {
+	if len(s.reports) == 0 {
+		return
+	}
+	if s.store == nil {
+		log.Printf("Warning: reports need a storage backend, set STORAGE_BACKEND")
+		return
+	}
+
+	now := time.Now()
+	next := make([]time.Time, len(s.reports))
+	for i, r := range s.reports {
+		next[i] = r.recurrence.Next(now)
+		log.Printf("  %s: %s, next at %s", r.title, r.recurrence, next[i].Format("Mon 15:04 MST"))
+	}
+
+	ticker := time.NewTicker(reportCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+
+		for i, r := range s.reports {
+			if now.Before(next[i]) {
+				continue
+			}
+			due := next[i]
+			next[i] = r.recurrence.Next(now)
+
+			if !r.sendsOn(due) {
+				log.Printf("Skipping %s: market closed", r.title)
+				continue
+			}
+			if err := s.sendReport(ctx, r, due); err != nil {
+				log.Printf("Failed to send %s: %v", r.title, err)
+			}
+		}
+	}
+}
+
+// sendsOn reports whether a run due at t is sent. Trading-day reports are
+// skipped when the market is closed, as far as the calendar covers.
+func (r report) sendsOn(t time.Time) bool {
+	nyse := calendar.NYSE()
+	return !r.tradingDay || !nyse.Covers(t) || nyse.IsTradingDay(t)
+}
+
+// sendReport summarizes the alert history covered by a report ending at end
+func (s *AlertService) sendReport(ctx context.Context, r report, end time.Time) error {
+	loc := r.recurrence.Location()
+	y, m, d := end.In(loc).Date()
+	from := time.Date(y, m, d-(r.days-1), 0, 0, 0, 0, loc)
+
+	records, err := s.store.ListAlerts(ctx, from, end)
+	if err != nil {
+		return err
+	}
+
+	message := s.formatReport(r, from, end, summarizeHistory(records, loc))
+	record := &storage.AlertRecord{
+		Kind:      storage.KindReport,
+		Message:   message,
+		EventTime: end,
+	}
+
+	deliveries, err := s.notifier.Send(ctx, message, nil)
+	record.SetDeliveries(deliveries)
+	s.recordAlert(ctx, record)
+	if record.Status == storage.StatusFailed {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+
+	log.Printf("Sent %s covering %d record(s)", r.title, len(records))
+	return nil
+}
+
+// historySummary counts the decisions, rule alerts and rankings in a
+// stretch of alert history
+type historySummary struct {
+	decisions  int
+	alerted    int                       // decisions sent or held for a digest
+	signals    map[string]int            // signal -> decisions
+	symbols    map[string]map[string]int // symbol -> signal -> decisions
+	scaleIns   []string                  // symbols with scale-in signals, first seen first
+	suppressed map[string]int            // reason -> suppressed alerts
+	failed     int                       // alerts no channel delivered
+	rules      int
+	rankings   int
+	days       map[string]int // local date (2006-01-02) -> decisions
+}
+
+func summarizeHistory(records []storage.AlertRecord, loc *time.Location) historySummary {
+	sum := historySummary{
+		signals:    make(map[string]int),
+		symbols:    make(map[string]map[string]int),
+		suppressed: make(map[string]int),
+		days:       make(map[string]int),
+	}
+
+	for _, r := range records {
+		switch r.Kind {
+		case storage.KindDecision:
+			sum.decisions++
+			sum.signals[r.Signal]++
+			if sum.symbols[r.Symbol] == nil {
+				sum.symbols[r.Symbol] = make(map[string]int)
+			}
+			sum.symbols[r.Symbol][r.Signal]++
+			sum.days[r.CreatedAt.In(loc).Format("2006-01-02")]++
+			if r.ScaleIn && !containsString(sum.scaleIns, r.Symbol) {
+				sum.scaleIns = append(sum.scaleIns, r.Symbol)
+			}
+			if r.Status != storage.StatusSuppressed && r.Status != storage.StatusFailed {
+				sum.alerted++
+			}
+		case storage.KindRule:
+			sum.rules++
+		case storage.KindRanking:
+			sum.rankings++
+		default:
+			// Digests and earlier reports repeat what is counted above
+			continue
+		}
+
+		switch r.Status {
+		case storage.StatusSuppressed:
+			sum.suppressed[r.SuppressionReason]++
+		case storage.StatusFailed:
+			sum.failed++
+		}
+	}
+	return sum
+}
+
+// formatReport renders a history summary for the notification channels
+func (s *AlertService) formatReport(r report, from, end time.Time, sum historySummary) string {
+	loc := r.recurrence.Location()
+	from, end = from.In(loc), end.In(loc)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📊 <b>%s</b>\n", r.title))
+	if r.days == 1 {
+		sb.WriteString(fmt.Sprintf("<i>%s, until %s</i>\n", end.Format("Mon Jan 2"), end.Format("15:04 MST")))
+	} else {
+		sb.WriteString(fmt.Sprintf("<i>%s – %s</i>\n", from.Format("Mon Jan 2"), end.Format("Mon Jan 2 15:04 MST")))
+	}
+
+	if sum.decisions == 0 && sum.rules == 0 && sum.rankings == 0 {
+		sb.WriteString("\nNo alerts recorded.")
+		return sb.String()
+	}
+
+	// Signals by type
+	sb.WriteString(fmt.Sprintf("\n<b>Signals:</b> %d (%d alerted)\n", sum.decisions, sum.alerted))
+	var parts []string
+	for _, signal := range reportSignalOrder(sum.signals) {
+		parts = append(parts, fmt.Sprintf("%s %s %d", signalEmoji(signal), signal, sum.signals[signal]))
+	}
+	if len(parts) > 0 {
+		sb.WriteString(strings.Join(parts, " · ") + "\n")
+	}
+	if len(sum.scaleIns) > 0 {
+		sb.WriteString(fmt.Sprintf("📈 Scale-in: %s\n", strings.Join(sum.scaleIns, ", ")))
+	}
+
+	// Per-day breakdown for multi-day reports
+	if r.days > 1 && sum.decisions > 0 {
+		sb.WriteString("\n<b>By day</b>\n")
+		for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+			sb.WriteString(fmt.Sprintf("%s: %d\n", day.Format("Mon Jan 2"), sum.days[day.Format("2006-01-02")]))
+		}
+	}
+
+	// Most active symbols
+	if top := topSymbols(sum.symbols, s.config.ReportTopSymbols); len(top) > 0 {
+		sb.WriteString("\n<b>Top symbols</b>\n")
+		for _, symbol := range top {
+			counts := sum.symbols[symbol]
+			total := 0
+			var signals []string
+			for _, signal := range reportSignalOrder(counts) {
+				total += counts[signal]
+				signals = append(signals, fmt.Sprintf("%s%d", signalEmoji(signal), counts[signal]))
+			}
+			sb.WriteString(fmt.Sprintf("%s %d · %s\n", symbol, total, strings.Join(signals, " ")))
+		}
+	}
+
+	// Suppressed alerts by reason
+	if len(sum.suppressed) > 0 {
+		reasons := make([]string, 0, len(sum.suppressed))
+		total := 0
+		for reason, n := range sum.suppressed {
+			reasons = append(reasons, reason)
+			total += n
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			if sum.suppressed[reasons[i]] != sum.suppressed[reasons[j]] {
+				return sum.suppressed[reasons[i]] > sum.suppressed[reasons[j]]
+			}
+			return reasons[i] < reasons[j]
+		})
+
+		sb.WriteString(fmt.Sprintf("\n<b>Suppressed:</b> %d\n", total))
+		for _, reason := range reasons {
+			sb.WriteString(fmt.Sprintf("%s: %d\n", strings.ReplaceAll(reason, "_", " "), sum.suppressed[reason]))
+		}
+	}
+
+	var other []string
+	if sum.rules > 0 {
+		other = append(other, fmt.Sprintf("%d rule alert(s)", sum.rules))
+	}
+	if sum.rankings > 0 {
+		other = append(other, fmt.Sprintf("%d ranking(s)", sum.rankings))
+	}
+	if sum.failed > 0 {
+		other = append(other, fmt.Sprintf("⚠️ %d failed deliver%s", sum.failed, plural(sum.failed, "y", "ies")))
+	}
+	if len(other) > 0 {
+		sb.WriteString("\n" + strings.Join(other, " · "))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// reportSignalOrder lists the signals in counts, known signals first
+func reportSignalOrder(counts map[string]int) []string {
+	var order, others []string
+	for _, signal := range digestSignalOrder {
+		if counts[signal] > 0 {
+			order = append(order, signal)
+		}
+	}
+	for signal := range counts {
+		if !containsString(digestSignalOrder, signal) {
+			others = append(others, signal)
+		}
+	}
+	sort.Strings(others)
+	return append(order, others...)
+}
+
+// topSymbols returns up to n symbols with the most decisions
+func topSymbols(symbols map[string]map[string]int, n int) []string {
+	totals := make(map[string]int, len(symbols))
+	list := make([]string, 0, len(symbols))
+	for symbol, counts := range symbols {
+		for _, c := range counts {
+			totals[symbol] += c
+		}
+		list = append(list, symbol)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if totals[list[i]] != totals[list[j]] {
+			return totals[list[i]] > totals[list[j]]
+		}
+		return list[i] < list[j]
+	})
+	if n >= 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+func TestSummarizeHistory(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 03:30 UTC on Mar 3 is still Mar 2 in New York
+	mon := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 3, 3, 3, 30, 0, 0, time.UTC)
+	tue := time.Date(2026, 3, 3, 15, 0, 0, 0, time.UTC)
+
+	records := []storage.AlertRecord{
+		{Kind: storage.KindDecision, Symbol: "AAPL", Signal: "BUY", Status: storage.StatusSent, CreatedAt: mon},
+		{Kind: storage.KindDecision, Symbol: "AAPL", Signal: "BUY", ScaleIn: true, Status: storage.StatusQueued, SuppressionReason: storage.ReasonQuietHours, CreatedAt: late},
+		{Kind: storage.KindDecision, Symbol: "AAPL", Signal: "SELL", Status: storage.StatusSuppressed, SuppressionReason: storage.ReasonCooldown, CreatedAt: tue},
+		{Kind: storage.KindDecision, Symbol: "MSFT", Signal: "BUY", ScaleIn: true, Status: storage.StatusFailed, CreatedAt: tue},
+		{Kind: storage.KindDecision, Symbol: "TSLA", Signal: "WATCH", Status: storage.StatusSuppressed, SuppressionReason: storage.ReasonCooldown, CreatedAt: tue},
+		{Kind: storage.KindDecision, Symbol: "AAPL", Signal: "BUY", ScaleIn: true, Status: storage.StatusPartial, CreatedAt: tue},
+		{Kind: storage.KindRule, RuleID: "oversold", Symbol: "AAPL", Status: storage.StatusSuppressed, SuppressionReason: storage.ReasonMuted, CreatedAt: tue},
+		{Kind: storage.KindRanking, Signal: "BUY", Status: storage.StatusSent, CreatedAt: tue},
+		{Kind: storage.KindDigest, Status: storage.StatusFailed, CreatedAt: tue},
+		{Kind: storage.KindReport, Status: storage.StatusSent, CreatedAt: tue},
+	}
+
+	sum := summarizeHistory(records, loc)
+
+	checks := []struct {
+		name      string
+		got, want int
+	}{
+		{"decisions", sum.decisions, 6},
+		{"alerted", sum.alerted, 3},
+		{"BUY", sum.signals["BUY"], 4},
+		{"SELL", sum.signals["SELL"], 1},
+		{"WATCH", sum.signals["WATCH"], 1},
+		{"AAPL BUY", sum.symbols["AAPL"]["BUY"], 3},
+		{"suppressed by cooldown", sum.suppressed[storage.ReasonCooldown], 2},
+		{"suppressed while muted", sum.suppressed[storage.ReasonMuted], 1},
+		{"suppression reasons", len(sum.suppressed), 2},
+		{"failed", sum.failed, 1},
+		{"rule alerts", sum.rules, 1},
+		{"rankings", sum.rankings, 1},
+		{"decisions on Mar 2", sum.days["2026-03-02"], 2},
+		{"decisions on Mar 3", sum.days["2026-03-03"], 4},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	if got := strings.Join(sum.scaleIns, ","); got != "AAPL,MSFT" {
+		t.Errorf("scale-ins = %s, want AAPL,MSFT", got)
+	}
+
+	s := &AlertService{config: &config.Config{ReportTopSymbols: 1, ReportTimezone: "America/New_York", ReportEOD: "mon-fri at 16:30"}}
+	reports := newReports(s.config)
+	if len(reports) != 1 {
+		t.Fatalf("newReports() = %d reports, want 1", len(reports))
+	}
+	message := s.formatReport(reports[0], mon, tue, sum)
+	for _, want := range []string{
+		"<b>Signals:</b> 6 (3 alerted)",
+		"BUY 4",
+		"Scale-in: AAPL, MSFT",
+		"AAPL 4 ·",
+		"<b>Suppressed:</b> 3\ncooldown: 2\nmuted: 1",
+		"1 rule alert(s) · 1 ranking(s) · ⚠️ 1 failed delivery",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("report does not contain %q:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "MSFT 1") {
+		t.Errorf("report lists more than the top symbol:\n%s", message)
+	}
+}
+
+func TestReportSendsOn(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eod := report{title: "End-of-Day Report", days: 1, tradingDay: true}
+	weekly := report{title: "Weekly Report", days: 7}
+
+	tests := []struct {
+		name   string
+		report report
+		due    time.Time
+		want   bool
+	}{
+		{"trading day", eod, time.Date(2026, 11, 25, 16, 30, 0, 0, loc), true},
+		{"holiday", eod, time.Date(2026, 11, 26, 16, 30, 0, 0, loc), false},
+		{"early close", eod, time.Date(2026, 11, 27, 16, 30, 0, 0, loc), true},
+		{"weekend", eod, time.Date(2026, 11, 28, 16, 30, 0, 0, loc), false},
+		{"holiday outside the calendar", eod, time.Date(2030, 12, 25, 16, 30, 0, 0, loc), true},
+		{"weekly report on a holiday", weekly, time.Date(2026, 11, 26, 17, 0, 0, 0, loc), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.sendsOn(tt.due); got != tt.want {
+				t.Errorf("sendsOn(%s) = %v, want %v", tt.due.Format("Mon Jan 2"), got, tt.want)
+			}
+		})
+	}
+}
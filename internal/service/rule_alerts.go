@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/rules"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// evaluateRules checks custom rule conditions for a symbol after its market
+// state changed and sends an alert for every rule that holds
+func (s *AlertService) evaluateRules(ctx context.Context, symbol string) error {
+	if s.rules == nil {
+		return nil
+	}
+
+	// Only evaluate against a fresh quote; stale prices would fire on old data
+	snap, ok := s.market.GetFresh(symbol)
+	if !ok {
+		return nil
+	}
+
+	for _, trigger := range s.rules.Evaluate(snap) {
+		if err := s.handleRuleTrigger(ctx, trigger); err != nil {
+			log.Printf("Failed to send rule alert %s for %s: %v", trigger.Rule.ID, trigger.Symbol, err)
+		}
+	}
+	return nil
+}
+
+// handleRuleTrigger applies cooldown and quiet hours to a triggered rule and
+// sends the alert. Edge rules are acknowledged only once the alert is sent
+// or held for the quiet hours digest.
+func (s *AlertService) handleRuleTrigger(ctx context.Context, trigger rules.Trigger) error {
+	cooldownMinutes := trigger.Rule.CooldownMinutes
+	if cooldownMinutes <= 0 {
+		cooldownMinutes = s.config.CooldownMinutes
+	}
+
+	// Rules fire again on every tick while their condition holds, edge rules
+	// until their alert goes out, so cooldown and mute hits are not written
+	// to history to keep it readable
+	key := ruleCooldownKey(trigger.Rule.ID, trigger.Symbol)
+	if !s.checkCooldownKey(key, time.Duration(cooldownMinutes)*time.Minute) {
+		return nil
+	}
+	if s.isMuted(ctx, trigger.Symbol) {
+		return nil
+	}
+	if !s.inAllowedSession(sessionKindRules, trigger.Snapshot.UpdatedAt()) {
+		return nil
+	}
+
+	message := s.formatRuleMessage(trigger)
+	record := &storage.AlertRecord{
+		Kind:      storage.KindRule,
+		Symbol:    trigger.Symbol,
+		RuleID:    trigger.Rule.ID,
+		Message:   message,
+		EventTime: trigger.Snapshot.UpdatedAt(),
+	}
+
+	channels := trigger.Rule.Channels
+	if len(channels) == 0 {
+		channels = s.notifier.DefaultChannels()
+	}
+
+	// Quiet hours hits are held for the digest, which counts as alerting.
+	// They are recorded once per cooldown period with their own cooldown, so
+	// a rule that could not be held still alerts as soon as quiet hours end.
+	if s.isQuietHours(ctx) {
+		quietKey := key + ":quiet"
+		if !s.checkCooldownKey(quietKey, time.Duration(cooldownMinutes)*time.Minute) {
+			return nil
+		}
+		log.Printf("Holding rule alert %s for %s: quiet hours active", trigger.Rule.ID, trigger.Symbol)
+		s.holdForDigest(ctx, record, channels, nil)
+		if record.Status == storage.StatusQueued {
+			s.rules.Ack(trigger.Rule.ID, trigger.Symbol)
+			s.setCooldown(ctx, storage.Cooldown{Key: key, LastAlert: time.Now()})
+		}
+		s.setCooldown(ctx, storage.Cooldown{Key: quietKey, LastAlert: time.Now()})
+		return nil
+	}
+	// Chats in their own quiet hours get it in their digest
+	s.holdForQuietChats(ctx, record, channels)
+
+	deliveries, err := s.notifier.Send(ctx, message, channels)
+	record.SetDeliveries(deliveries)
+	s.recordAlert(ctx, record)
+	if record.Status == storage.StatusFailed {
+		return fmt.Errorf("failed to send rule alert: %w", err)
+	}
+	if err != nil {
+		log.Printf("Rule alert %s for %s only partially delivered: %v", trigger.Rule.ID, trigger.Symbol, err)
+	}
+
+	s.rules.Ack(trigger.Rule.ID, trigger.Symbol)
+	s.setCooldown(ctx, storage.Cooldown{Key: key, LastAlert: time.Now()})
+
+	log.Printf("Sent rule alert %s for %s", trigger.Rule.ID, trigger.Symbol)
+	return nil
+}
+
+// formatRuleMessage formats a triggered custom rule into a Telegram message
+func (s *AlertService) formatRuleMessage(trigger rules.Trigger) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🚨 <b>ALERT: %s %s</b>\n\n", trigger.Symbol, html.EscapeString(trigger.Rule.DisplayName())))
+	sb.WriteString(fmt.Sprintf("Symbol: %s\n", trigger.Symbol))
+	sb.WriteString(fmt.Sprintf("Price: $%.2f\n", trigger.Snapshot.Price))
+
+	for _, name := range trigger.Fields {
+		if name == "price" {
+			continue
+		}
+		if value, ok := trigger.Values[name]; ok {
+			sb.WriteString(fmt.Sprintf("%s: %.2f\n", strings.ToUpper(name), value))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\nCondition: <code>%s</code>\n", html.EscapeString(trigger.Condition)))
+
+	if trigger.Rule.Message != "" {
+		sb.WriteString(fmt.Sprintf("\nAction: %s\n", html.EscapeString(trigger.Rule.Message)))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n🕐 %s · %s", trigger.Snapshot.QuoteUpdatedAt.Format("2006-01-02 15:04:05 MST"),
+		s.sessionLabel(trigger.Snapshot.QuoteUpdatedAt)))
+
+	return sb.String()
+}
+
+func ruleCooldownKey(ruleID, symbol string) string {
+	return "rule:" + ruleID + ":" + symbol
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/market"
+	"github.com/trogers1052/alert-service/internal/rules"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// ruleSnapshot is a fresh snapshot of a symbol with the given RSI
+func ruleSnapshot(symbol string, rsi float64) market.Snapshot {
+	return market.Snapshot{Symbol: symbol, Price: 100, QuoteUpdatedAt: time.Now(),
+		Indicators: map[string]float64{"rsi_14": rsi}}
+}
+
+// fireRules evaluates the rules for a snapshot and handles every trigger
+func fireRules(t *testing.T, s *AlertService, snap market.Snapshot) {
+	t.Helper()
+	for _, trigger := range s.rules.Evaluate(snap) {
+		if err := s.handleRuleTrigger(context.Background(), trigger); err != nil {
+			t.Fatalf("handleRuleTrigger(%s): %v", trigger.Rule.ID, err)
+		}
+	}
+}
+
+func TestRuleAlertsInQuietHours(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	s, telegram := newTestService(t, map[string]string{"QUIET_HOURS_DIGEST": "true"}, store)
+	s.rules = rules.NewEngine()
+	oversold := rules.Rule{ID: "oversold", Condition: "rsi_14 < 30", Trigger: rules.TriggerEdge, Symbols: []string{"AAPL"}}
+	priced := rules.Rule{ID: "priced", Condition: "price > 0", Symbols: []string{"MSFT"}}
+	if err := s.rules.Load(&rules.RuleSet{Rules: []rules.Rule{oversold, priced}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everyone quiet: the crossing is held for each chat and acknowledged
+	setQuietChats(t, store, 1, 2)
+	fireRules(t, s, ruleSnapshot("AAPL", 40))
+	fireRules(t, s, ruleSnapshot("AAPL", 25))
+	if got := queuedCounts(t, store, ""); !equalCounts(got, map[string]int{"telegram chat 1": 1, "telegram chat 2": 1}) {
+		t.Fatalf("queued during quiet hours = %v, want the rule alert for both chats", got)
+	}
+	if got := s.rules.Evaluate(ruleSnapshot("AAPL", 20)); len(got) != 0 {
+		t.Errorf("edge rule still armed after its alert was held")
+	}
+	if len(telegram.messages()) != 0 {
+		t.Errorf("rule alert sent live during quiet hours: %q", telegram.messages())
+	}
+
+	// Chat 2 wakes up and gets the rule in its digest
+	setQuietChats(t, store, 1)
+	if err := s.flushDigest(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := telegram.messages(); len(msgs) != 1 || !strings.Contains(msgs[0], "🚨 <b>AAPL</b> oversold") {
+		t.Errorf("digest = %q, want the oversold rule listed", msgs)
+	}
+
+	// A live alert reaches chat 2 and is held for chat 1, still quiet
+	telegram.reset()
+	fireRules(t, s, ruleSnapshot("MSFT", 50))
+	if len(telegram.chats) != 1 || len(telegram.chats[0]) != 1 || telegram.chats[0][0] != 2 {
+		t.Errorf("live rule alert went to %v, want only chat 2", telegram.chats)
+	}
+	if got := queuedCounts(t, store, ""); got["telegram chat 1"] != 2 {
+		t.Errorf("queued = %v, want the live alert held for chat 1 too", got)
+	}
+}
+
+func TestRuleAlertsSuppressedInQuietHours(t *testing.T) {
+	store := newTestStore(t)
+	s, _ := newTestService(t, map[string]string{"QUIET_HOURS_DIGEST": "false"}, store)
+	s.rules = rules.NewEngine()
+	if err := s.rules.Load(&rules.RuleSet{Rules: []rules.Rule{{ID: "oversold", Condition: "rsi_14 < 30", Trigger: rules.TriggerEdge}}}); err != nil {
+		t.Fatal(err)
+	}
+	setQuietChats(t, store, 1, 2)
+
+	fireRules(t, s, ruleSnapshot("AAPL", 40))
+	fireRules(t, s, ruleSnapshot("AAPL", 25))
+	history, err := store.ListAlerts(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].SuppressionReason != storage.ReasonQuietHours {
+		t.Fatalf("history = %+v, want one alert suppressed for quiet hours", history)
+	}
+
+	// Nothing was held, so the crossing is kept for when quiet hours end
+	if got := s.rules.Evaluate(ruleSnapshot("AAPL", 25)); len(got) != 1 {
+		t.Errorf("edge rule disarmed by a suppressed alert")
+	}
+}
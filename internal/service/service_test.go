@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/market"
+	"github.com/trogers1052/alert-service/internal/notify"
+	"github.com/trogers1052/alert-service/internal/storage"
+	"github.com/trogers1052/alert-service/internal/storage/sqlite"
+)
+
+// fakeNotifier records the messages sent over one channel. With recipients
+// set it also records the chats each message would reach.
+type fakeNotifier struct {
+	name       string
+	recipients func(ctx context.Context) ([]int64, error)
+
+	mu    sync.Mutex
+	sent  []string
+	chats [][]int64
+}
+
+func (f *fakeNotifier) Name() string { return f.name }
+
+func (f *fakeNotifier) SendMessage(ctx context.Context, message string) error {
+	var chats []int64
+	if f.recipients != nil {
+		var err error
+		if chats, err = f.recipients(ctx); err != nil {
+			return err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, message)
+	f.chats = append(f.chats, chats)
+	return nil
+}
+
+func (f *fakeNotifier) messages() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sent...)
+}
+
+func (f *fakeNotifier) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent, f.chats = nil, nil
+}
+
+// newTestService creates a service from the environment in env, sending to
+// a fake telegram channel that resolves chats like the real client, plus
+// any extra channels. store may be nil.
+func newTestService(t *testing.T, env map[string]string, store storage.Store, extra ...notify.Notifier) (*AlertService, *fakeNotifier) {
+	t.Helper()
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test")
+	t.Setenv("TELEGRAM_CHAT_ID", "1")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+
+	telegram := &fakeNotifier{name: "telegram"}
+	notifier := notify.NewDispatcher(append([]notify.Notifier{telegram}, extra...)...)
+	s := NewAlertService(cfg, notifier, market.NewState(0), nil, store)
+	telegram.recipients = s.AlertRecipients
+	return s, telegram
+}
+
+// newTestStore opens a migrated sqlite store in a temporary directory
+func newTestStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "alerts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return store
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/calendar"
+	"github.com/trogers1052/alert-service/internal/config"
+)
+
+// Session filter keys for alerts that are not decision signals
+const (
+	sessionKindRankings = "RANKINGS"
+	sessionKindRules    = "RULES"
+)
+
+func newSessionFilter(cfg *config.Config) calendar.Filter {
+	// The config was validated on load
+	filter, err := calendar.ParseFilter(cfg.AlertSessions)
+	if err != nil {
+		log.Printf("Warning: market session filters disabled: %v", err)
+		return nil
+	}
+	return filter
+}
+
+// inAllowedSession reports whether an alert of the given kind may be sent
+// for an event at t. A zero t means now.
+func (s *AlertService) inAllowedSession(kind string, t time.Time) bool {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return s.sessions.Allows(s.calendar, kind, t)
+}
+
+// sessionLabel names the market session at t for messages
+func (s *AlertService) sessionLabel(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if holiday, ok := s.calendar.Holiday(t); ok {
+		return "Market closed (" + holiday + ")"
+	}
+	label := s.calendar.Session(t).Label()
+	if s.calendar.IsEarlyClose(t) {
+		label += " (early close)"
+	}
+	return label
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+// Decision metadata keys that may carry a stop: an absolute stop price, or
+// the distance from the entry price to the stop
+var (
+	stopPriceKeys    = []string{"stop_loss", "stop_price", "stop"}
+	stopDistanceKeys = []string{"stop_distance"}
+)
+
+// positionSize is a suggested share count for a BUY: the number of shares
+// whose loss at the stop equals the risk budget, capped by the maximum
+// position value
+type positionSize struct {
+	Shares    int
+	Price     float64
+	Stop      float64
+	StopBasis string  // where the stop came from, e.g. "2.0× atr_14 1.90"
+	Risk      float64 // dollars lost if the stop is hit with Shares
+	Budget    float64 // dollars the risk percentage allows per trade
+	Capped    bool    // Shares was limited by the maximum position value
+	Held      float64 // shares already held, for scale-ins
+}
+
+// sizePosition suggests a share count for a BUY decision. It needs sizing
+// to be configured, an entry price and a stop distance.
+func (s *AlertService) sizePosition(data *models.DecisionData) (*positionSize, bool) {
+	if s.config.SizingAccountSize <= 0 || data.Signal != models.SignalBuy {
+		return nil, false
+	}
+	price, ok := s.entryPrice(data)
+	if !ok {
+		return nil, false
+	}
+	distance, basis, ok := s.stopDistance(data, price)
+	if !ok {
+		return nil, false
+	}
+
+	account := s.config.SizingAccountSize
+	size := &positionSize{
+		Price:     price,
+		Stop:      price - distance,
+		StopBasis: basis,
+		Budget:    account * s.config.SizingRiskPercent / 100,
+	}
+	size.Shares = int(math.Floor(size.Budget / distance))
+
+	// Keep the whole position, including shares already held, under the cap
+	if position, held, _ := s.heldPosition(data.Symbol); held && position.Quantity > 0 {
+		size.Held = position.Quantity
+	}
+	if s.config.SizingMaxPositionPercent > 0 {
+		room := account*s.config.SizingMaxPositionPercent/100 - size.Held*price
+		if limit := int(math.Floor(math.Max(room, 0) / price)); limit < size.Shares {
+			size.Shares = limit
+			size.Capped = true
+		}
+	}
+	size.Risk = float64(size.Shares) * distance
+	return size, true
+}
+
+// stopDistance returns the distance from the entry price to the stop: from
+// a stop price or distance in the metadata, or a multiple of the stop
+// indicator from the decision's indicators or the market state
+func (s *AlertService) stopDistance(data *models.DecisionData, price float64) (float64, string, bool) {
+	if stop, ok := metadataFloat(data, stopPriceKeys...); ok && stop > 0 && stop < price {
+		return price - stop, "from signal", true
+	}
+	if distance, ok := metadataFloat(data, stopDistanceKeys...); ok && distance > 0 && distance < price {
+		return distance, "from signal", true
+	}
+
+	name := s.config.SizingStopIndicator
+	value, ok := 0.0, false
+	for key, v := range data.IndicatorsSnapshot {
+		if strings.EqualFold(key, name) && v > 0 {
+			value, ok = v, true
+			break
+		}
+	}
+	if !ok && s.market != nil {
+		if snap, found := s.market.Get(data.Symbol); found {
+			value, ok = snap.Indicator(name)
+		}
+	}
+	distance := value * s.config.SizingStopMultiple
+	if !ok || distance <= 0 || distance >= price {
+		return 0, "", false
+	}
+	return distance, fmt.Sprintf("%.1f× %s %.2f", s.config.SizingStopMultiple, name, value), true
+}
+
+// formatSizing renders the suggested position size for a BUY or scale-in
+func (s *AlertService) formatSizing(size *positionSize, scaleIn bool) string {
+	var sb strings.Builder
+	sb.WriteString("📐 <b>Position Sizing:</b>\n")
+	if size.Shares <= 0 {
+		if size.Capped {
+			sb.WriteString(fmt.Sprintf("  • No room: position is at the %.0f%% cap\n", s.config.SizingMaxPositionPercent))
+		} else {
+			sb.WriteString(fmt.Sprintf("  • Stop $%.2f is too wide for a $%.2f risk budget\n", size.Stop, size.Budget))
+		}
+		return sb.String() + "\n"
+	}
+
+	verb := "Buy"
+	if scaleIn {
+		verb = "Add"
+	}
+	sb.WriteString(fmt.Sprintf("  • %s %d sh @ $%.2f (~$%.2f)\n", verb, size.Shares, size.Price, float64(size.Shares)*size.Price))
+	if scaleIn && size.Held > 0 {
+		sb.WriteString(fmt.Sprintf("  • Position %s → %s sh\n", formatQuantity(size.Held), formatQuantity(size.Held+float64(size.Shares))))
+	}
+	sb.WriteString(fmt.Sprintf("  • Stop $%.2f (%s)\n", size.Stop, size.StopBasis))
+	sb.WriteString(fmt.Sprintf("  • Risk $%.2f (%.1f%% of $%.0f)\n", size.Risk, size.Risk/s.config.SizingAccountSize*100, s.config.SizingAccountSize))
+	if size.Capped {
+		sb.WriteString(fmt.Sprintf("  • Capped at %.0f%% of the account\n", s.config.SizingMaxPositionPercent))
+	}
+	return sb.String() + "\n"
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+func TestStopDistance(t *testing.T) {
+	s, _ := newTestService(t, map[string]string{
+		"SIZING_ACCOUNT_SIZE":   "10000",
+		"SIZING_STOP_INDICATOR": "atr_14",
+		"SIZING_STOP_MULTIPLE":  "2",
+	}, nil)
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		atr      float64
+		want     float64
+		basis    string
+		ok       bool
+	}{
+		{"stop price", map[string]interface{}{"stop_loss": 95.0}, 1, 5, "from signal", true},
+		{"stop price as a string", map[string]interface{}{"stop": "97.5"}, 1, 2.5, "from signal", true},
+		{"stop distance", map[string]interface{}{"stop_distance": 4.0}, 1, 4, "from signal", true},
+		{"stop above the entry falls back to the indicator", map[string]interface{}{"stop_loss": 105.0}, 1, 2, "2.0× atr_14 1.00", true},
+		{"zero stop price falls back", map[string]interface{}{"stop_price": 0.0}, 1.5, 3, "2.0× atr_14 1.50", true},
+		{"negative stop price falls back", map[string]interface{}{"stop_price": -5.0}, 1.5, 3, "2.0× atr_14 1.50", true},
+		{"zero stop distance falls back", map[string]interface{}{"stop_distance": 0.0}, 1, 2, "2.0× atr_14 1.00", true},
+		{"negative stop distance falls back", map[string]interface{}{"stop_distance": -3.0}, 1, 2, "2.0× atr_14 1.00", true},
+		{"distance wider than the price", map[string]interface{}{"stop_distance": 150.0}, 0, 0, "", false},
+		{"no stop and no indicator", nil, 0, 0, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy, Metadata: tt.metadata}
+			if tt.atr > 0 {
+				data.IndicatorsSnapshot = map[string]float64{"atr_14": tt.atr}
+			}
+			got, basis, ok := s.stopDistance(data, 100)
+			if got != tt.want || basis != tt.basis || ok != tt.ok {
+				t.Errorf("stopDistance() = %v, %q, %v; want %v, %q, %v", got, basis, ok, tt.want, tt.basis, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSizePosition(t *testing.T) {
+	s, _ := newTestService(t, map[string]string{
+		"SIZING_ACCOUNT_SIZE":         "10000",
+		"SIZING_RISK_PERCENT":         "1",
+		"SIZING_MAX_POSITION_PERCENT": "25",
+	}, nil)
+
+	tests := []struct {
+		name   string
+		stop   float64
+		shares int
+		capped bool
+	}{
+		{"risk budget decides", 95, 20, false},
+		{"capped by the maximum position", 99.5, 25, true},
+	}
+	for _, tt := range tests {
+		data := &models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy,
+			Metadata: map[string]interface{}{"price": 100.0, "stop_loss": tt.stop}}
+		size, ok := s.sizePosition(data)
+		if !ok {
+			t.Fatalf("%s: sizePosition() not ok", tt.name)
+		}
+		if size.Shares != tt.shares || size.Capped != tt.capped {
+			t.Errorf("%s: %d shares (capped %v), want %d (capped %v)", tt.name, size.Shares, size.Capped, tt.shares, tt.capped)
+		}
+	}
+}
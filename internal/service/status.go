@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/cooldown"
+)
+
+// Status is a point-in-time view of the service's alerting state
+type Status struct {
+	Time      time.Time        `json:"time"`
+	Cooldowns []CooldownStatus `json:"cooldowns"`
+}
+
+// CooldownStatus is the effective cooldown for a symbol and signal
+type CooldownStatus struct {
+	Symbol    string    `json:"symbol"`
+	Signal    string    `json:"signal"`
+	LastAlert time.Time `json:"last_alert"`
+	Level     int       `json:"level"`     // escalation level in force, after decay
+	Cooldown  string    `json:"cooldown"`  // effective duration at the current level
+	Remaining string    `json:"remaining"` // empty once the cooldown has elapsed
+}
+
+// Status returns the service's current alerting state
+func (s *AlertService) Status() Status {
+	now := time.Now()
+	return Status{
+		Time:      now,
+		Cooldowns: s.cooldownStatus(now),
+	}
+}
+
+// cooldownStatus lists the decision cooldowns, most recent alert first
+func (s *AlertService) cooldownStatus(now time.Time) []CooldownStatus {
+	s.cooldownMu.RLock()
+	defer s.cooldownMu.RUnlock()
+
+	out := []CooldownStatus{}
+	for key, entry := range s.cooldowns {
+		// Only symbol and signal keys; bare symbols and rule keys are skipped
+		symbol, signal, ok := strings.Cut(key, ":")
+		if !ok || symbol == "rule" {
+			continue
+		}
+
+		level := s.policy.Level(now, &cooldown.Entry{At: entry.LastAlert, Level: entry.Level})
+		effective := s.policy.Effective(symbol, signal, level)
+		status := CooldownStatus{
+			Symbol:    symbol,
+			Signal:    signal,
+			LastAlert: entry.LastAlert,
+			Level:     level,
+			Cooldown:  effective.String(),
+		}
+		if remaining := effective - now.Sub(entry.LastAlert); remaining > 0 {
+			status.Remaining = remaining.Round(time.Second).String()
+		}
+		out = append(out, status)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].LastAlert.After(out[j].LastAlert)
+	})
+	return out
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"html"
+	"log"
+	"strings"
+
+	"github.com/trogers1052/alert-service/internal/classify"
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+// SetClassifier replaces the built-in signal tags
+func (s *AlertService) SetClassifier(c *classify.Classifier) {
+	s.classifier = c
+
+	known := s.notifier.DefaultChannels()
+	for _, t := range c.Tags() {
+		for _, channel := range t.Channels {
+			if !containsString(known, channel) {
+				log.Printf("Warning: tag %q routed to unknown channel %q", t.Name, channel)
+			}
+		}
+	}
+}
+
+// decisionTags classifies a decision. With positions loaded the scale-in
+// tag follows holdings rather than its patterns: a BUY on a held long
+// position is a scale-in, any other decision is not. Classifiers without a
+// scale-in tag never report scale-ins.
+func (s *AlertService) decisionTags(data *models.DecisionData) []classify.Tag {
+	tags := s.classifier.Classify(data)
+
+	position, held, known := s.heldPosition(data.Symbol)
+	if !known {
+		return tags
+	}
+	kept := tags[:0]
+	for _, t := range tags {
+		if t.Name != classify.ScaleIn {
+			kept = append(kept, t)
+		}
+	}
+	if data.Signal == models.SignalBuy && held && position.Quantity > 0 {
+		if scaleIn, ok := s.classifier.Lookup(classify.ScaleIn); ok {
+			kept = append([]classify.Tag{scaleIn}, kept...)
+		}
+	}
+	return kept
+}
+
+// isScaleInSignal reports whether a BUY adds to an existing position
+func (s *AlertService) isScaleInSignal(data *models.DecisionData) bool {
+	return data.Signal == models.SignalBuy && classify.Has(s.decisionTags(data), classify.ScaleIn)
+}
+
+// tagStyle returns the header emoji and label of a decision: the first
+// tag setting each wins over the signal's defaults
+func tagStyle(tags []classify.Tag, emoji, label string) (string, string) {
+	emojiSet, labelSet := false, false
+	for _, t := range tags {
+		if t.Emoji != "" && !emojiSet {
+			emoji, emojiSet = t.Emoji, true
+		}
+		if t.Label != "" && !labelSet {
+			label, labelSet = t.Label, true
+		}
+	}
+	return emoji, html.EscapeString(label)
+}
+
+// tagChannels returns the channels of the first tag that routes alerts
+func tagChannels(tags []classify.Tag) []string {
+	for _, t := range tags {
+		if len(t.Channels) > 0 {
+			return t.Channels
+		}
+	}
+	return nil
+}
+
+// formatTagLine lists a decision's tags other than the one that labelled
+// the header and scale-in, which has its own header line
+func formatTagLine(tags []classify.Tag) string {
+	labelled := false
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t.Label != "" && !labelled {
+			labelled = true
+			continue
+		}
+		if t.Name != classify.ScaleIn {
+			names = append(names, html.EscapeString(t.Name))
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "🏷 " + strings.Join(names, ", ") + "\n"
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/trogers1052/alert-service/internal/classify"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/portfolio"
+)
+
+func TestDecisionTagsScaleIn(t *testing.T) {
+	breakout := classify.Tag{Name: "breakout", Rules: []string{"breakout"}}
+
+	tests := []struct {
+		name      string
+		tags      []classify.Tag // nil keeps the built-in tags
+		positions []portfolio.Position
+		data      models.DecisionData
+		scaleIn   bool
+	}{
+		{
+			name:    "built-in tag from reasoning without positions",
+			data:    models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy, PrimaryReasoning: "average down"},
+			scaleIn: true,
+		},
+		{
+			name:      "held position makes a BUY a scale-in",
+			positions: []portfolio.Position{{Symbol: "AAPL", Quantity: 10}},
+			data:      models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy},
+			scaleIn:   true,
+		},
+		{
+			name:      "reasoning alone does not when positions say otherwise",
+			positions: []portfolio.Position{{Symbol: "MSFT", Quantity: 10}},
+			data:      models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy, PrimaryReasoning: "average down"},
+			scaleIn:   false,
+		},
+		{
+			name:      "short position is not scaled into",
+			positions: []portfolio.Position{{Symbol: "AAPL", Quantity: -10}},
+			data:      models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy},
+			scaleIn:   false,
+		},
+		{
+			name:      "tags file without scale-in",
+			tags:      []classify.Tag{breakout},
+			positions: []portfolio.Position{{Symbol: "AAPL", Quantity: 10}},
+			data:      models.DecisionData{Symbol: "AAPL", Signal: models.SignalBuy},
+			scaleIn:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestService(t, nil, nil)
+			if tt.tags != nil {
+				c, err := classify.New(tt.tags)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s.SetClassifier(c)
+			}
+			if tt.positions != nil {
+				book := portfolio.NewBook()
+				book.Replace(tt.positions)
+				s.SetPositions(book)
+			}
+
+			if got := s.isScaleInSignal(&tt.data); got != tt.scaleIn {
+				t.Errorf("isScaleInSignal() = %v, want %v", got, tt.scaleIn)
+			}
+		})
+	}
+}
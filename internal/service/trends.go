@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/models"
+	"github.com/trogers1052/alert-service/internal/storage"
+	"github.com/trogers1052/alert-service/internal/trend"
+)
+
+// Rising confidence that re-alerts inside the cooldown
+const (
+	momentumRising   = "rising"   // enough consecutive increases
+	momentumCrossing = "crossing" // crossed MinConfidence from below
+)
+
+// trendLoadWindow bounds the history read at startup when readings never expire
+const trendLoadWindow = 7 * 24 * time.Hour
+
+// newTrendTracker returns the confidence trend tracker, or nil when trends
+// are disabled
+func newTrendTracker(cfg *config.Config) *trend.Tracker {
+	if cfg.ConfidenceTrendPoints <= 0 {
+		return nil
+	}
+	return trend.NewTracker(cfg.ConfidenceTrendPoints, time.Duration(cfg.ConfidenceTrendHours)*time.Hour)
+}
+
+// trackConfidence records a decision's confidence, alerted or not, and
+// returns the recent readings for its symbol and signal
+func (s *AlertService) trackConfidence(event *models.DecisionEvent) []trend.Point {
+	if s.trends == nil {
+		return nil
+	}
+	at := event.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return s.trends.Add(event.Data.Symbol, event.Data.Signal, trend.Point{Confidence: event.Data.Confidence, At: at})
+}
+
+// confidenceMomentum reports whether the latest reading starts rising
+// momentum: the increase that completes the configured streak, or a
+// crossing of the confidence threshold. Longer streaks do not re-alert, so
+// a steady climb bypasses the cooldown once.
+func (s *AlertService) confidenceMomentum(points []trend.Point) string {
+	if streak := s.config.ConfidenceRisingStreak; streak > 0 && trend.Streak(points) == streak {
+		return momentumRising
+	}
+	if s.config.ConfidenceCrossing && trend.Crossed(points, s.config.MinConfidence) {
+		return momentumCrossing
+	}
+	return ""
+}
+
+// formatTrendLine renders the recent confidence of a symbol and signal,
+// e.g. "📈 Trend: ▁▃▅█ 62% → 80% · up 3× in a row"
+func (s *AlertService) formatTrendLine(data *models.DecisionData) string {
+	if s.trends == nil {
+		return ""
+	}
+	points := s.trends.Get(data.Symbol, data.Signal)
+	if len(points) < 2 {
+		return ""
+	}
+
+	first, last := points[0].Confidence, points[len(points)-1].Confidence
+	emoji := "📈"
+	if last < first {
+		emoji = "📉"
+	}
+	line := fmt.Sprintf("%s Trend: %s %.0f%% → %.0f%%", emoji, trend.Sparkline(points), first*100, last*100)
+	streak := trend.Streak(points)
+	switch {
+	case s.config.ConfidenceRisingStreak > 0 && streak >= s.config.ConfidenceRisingStreak:
+		line += fmt.Sprintf(" · up %d× in a row", streak)
+	case s.confidenceMomentum(points) == momentumCrossing:
+		line += fmt.Sprintf(" · crossed %.0f%%", s.config.MinConfidence*100)
+	}
+	return line + "\n"
+}
+
+// loadTrends restores recent confidence readings from the alert history
+func (s *AlertService) loadTrends() {
+	if s.store == nil || s.trends == nil {
+		return
+	}
+
+	window := time.Duration(s.config.ConfidenceTrendHours) * time.Hour
+	if window <= 0 {
+		window = trendLoadWindow
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	records, err := s.store.ListAlerts(ctx, now.Add(-window), now)
+	if err != nil {
+		log.Printf("Warning: failed to load confidence trends: %v", err)
+		return
+	}
+	for _, r := range records {
+		if r.Kind != storage.KindDecision {
+			continue
+		}
+		at := r.EventTime
+		if at.IsZero() {
+			at = r.CreatedAt
+		}
+		s.trends.Add(r.Symbol, r.Signal, trend.Point{Confidence: r.Confidence, At: at})
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/trend"
+)
+
+func TestConfidenceMomentum(t *testing.T) {
+	series := func(confidences ...float64) []trend.Point {
+		points := make([]trend.Point, len(confidences))
+		for i, c := range confidences {
+			points[i] = trend.Point{Confidence: c, At: time.Unix(int64(i), 0)}
+		}
+		return points
+	}
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		points []trend.Point
+		want   string
+	}{
+		{"off by default", nil, series(0.5, 0.6, 0.7, 0.8), ""},
+		{"streak completed", map[string]string{"CONFIDENCE_RISING_STREAK": "3"}, series(0.5, 0.6, 0.7, 0.8), momentumRising},
+		{"streak not yet reached", map[string]string{"CONFIDENCE_RISING_STREAK": "3"}, series(0.6, 0.7, 0.8), ""},
+		{"longer streak does not re-alert", map[string]string{"CONFIDENCE_RISING_STREAK": "3"}, series(0.5, 0.6, 0.7, 0.8, 0.9), ""},
+		{"crossing", map[string]string{"CONFIDENCE_CROSSING": "true", "MIN_CONFIDENCE": "0.7"}, series(0.65, 0.75), momentumCrossing},
+		{"no crossing from above", map[string]string{"CONFIDENCE_CROSSING": "true", "MIN_CONFIDENCE": "0.7"}, series(0.75, 0.8), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestService(t, tt.env, nil)
+			if got := s.confidenceMomentum(tt.points); got != tt.want {
+				t.Errorf("confidenceMomentum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
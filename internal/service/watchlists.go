@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/classify"
+	"github.com/trogers1052/alert-service/internal/config"
+	"github.com/trogers1052/alert-service/internal/storage"
+	"github.com/trogers1052/alert-service/internal/watchlist"
+)
+
+// Handling of decisions for symbols on no active watchlist
+const (
+	unlistedAllow = "allow"
+	unlistedDrop  = "drop"
+	unlistedRoute = "route" // send to the low-priority channels
+)
+
+// newWatchlists loads the configured watchlists, or the stored ones when
+// there is a store; configured watchlists seed an empty store
+func newWatchlists(cfg *config.Config, store storage.Store) *watchlist.Set {
+	// The config was validated on load
+	lists, err := watchlist.Parse(cfg.Watchlists)
+	if err != nil {
+		log.Printf("Warning: configured watchlists ignored: %v", err)
+	}
+	if store == nil {
+		return watchlist.NewSet(lists)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stored, err := storage.EnsureWatchlists(ctx, store, lists)
+	if err != nil {
+		log.Printf("Warning: failed to load watchlists, using configured ones: %v", err)
+		return watchlist.NewSet(lists)
+	}
+	return watchlist.NewSet(stored)
+}
+
+// newWatchlistRoutes parses the channels each watchlist routes to
+func newWatchlistRoutes(cfg *config.Config) map[string][]string {
+	// The config was validated on load
+	routes, err := config.ParseChannelMap(cfg.WatchlistChannels)
+	if err != nil {
+		log.Printf("Warning: watchlist routing disabled: %v", err)
+		return nil
+	}
+	return routes
+}
+
+// checkWatchlistChannels warns about watchlist routes to unknown channels
+func (s *AlertService) checkWatchlistChannels() {
+	known := s.notifier.DefaultChannels()
+	for name, channels := range s.routes {
+		for _, channel := range channels {
+			if !containsString(known, channel) {
+				log.Printf("Warning: watchlist %q routed to unknown channel %q", name, channel)
+			}
+		}
+	}
+	for _, channel := range s.config.LowPriorityChannels {
+		if !containsString(known, channel) {
+			log.Printf("Warning: unknown low-priority channel %q", channel)
+		}
+	}
+}
+
+// isUnlisted reports whether a symbol is on no active watchlist. With no
+// active watchlists at all every symbol counts as listed.
+func (s *AlertService) isUnlisted(symbol string) bool {
+	return s.watchlists.HasActive() && len(s.watchlists.ListsOf(symbol)) == 0
+}
+
+// decisionChannels returns the channels a decision for the symbol goes to:
+// the channels of its first routing tag, the low-priority channels for
+// low-priority alerts, the channels of the symbol's routed watchlists, or
+// the default channels
+func (s *AlertService) decisionChannels(symbol string, tags []classify.Tag, lowPriority bool) []string {
+	if channels := tagChannels(tags); len(channels) > 0 {
+		return channels
+	}
+	if lowPriority && len(s.config.LowPriorityChannels) > 0 {
+		return s.config.LowPriorityChannels
+	}
+
+	var channels []string
+	for _, name := range s.watchlists.ListsOf(symbol) {
+		for _, channel := range s.routes[name] {
+			if !containsString(channels, channel) {
+				channels = append(channels, channel)
+			}
+		}
+	}
+	if len(channels) == 0 {
+		return s.notifier.DefaultChannels()
+	}
+	return channels
+}
+
+// Watchlists returns every watchlist sorted by name
+func (s *AlertService) Watchlists() []watchlist.Watchlist {
+	return s.watchlists.All()
+}
+
+// saveWatchlist stores a watchlist and applies it
+func (s *AlertService) saveWatchlist(ctx context.Context, w watchlist.Watchlist) error {
+	w.UpdatedAt = time.Now()
+	if s.store != nil {
+		if err := s.store.SaveWatchlist(ctx, w); err != nil {
+			return err
+		}
+	}
+	s.watchlists.Put(w)
+	return nil
+}
+
+// deleteWatchlist removes a watchlist from the store and the set
+func (s *AlertService) deleteWatchlist(ctx context.Context, name string) error {
+	if s.store != nil {
+		if err := s.store.DeleteWatchlist(ctx, name); err != nil {
+			return err
+		}
+	}
+	s.watchlists.Delete(name)
+	return nil
+}
+
+const watchlistUsage = "Usage: /watchlist [add|remove|delete|on|off] &lt;name&gt; [symbols]"
+
+// WatchlistCommand answers /watchlist: without arguments it lists the
+// watchlists; "add" and "remove" change a list's symbols, "delete" drops a
+// list, and "on" and "off" switch it between active and inactive
+func (s *AlertService) WatchlistCommand(ctx context.Context, chatID int64, args []string) (string, error) {
+	if len(args) == 0 {
+		return s.formatWatchlists(), nil
+	}
+	if len(args) < 2 {
+		return watchlistUsage, nil
+	}
+
+	action, name := strings.ToLower(args[0]), watchlist.NormalizeName(args[1])
+	symbols := args[2:]
+	current, exists := s.watchlists.Get(name)
+	if !exists && action != "add" {
+		return fmt.Sprintf("No watchlist named <b>%s</b>.", html.EscapeString(name)), nil
+	}
+
+	switch action {
+	case "add":
+		if len(symbols) == 0 {
+			return watchlistUsage, nil
+		}
+		if !exists {
+			current = watchlist.Watchlist{Name: name, Active: true}
+		}
+		current.Symbols = append(current.Symbols, symbols...)
+	case "remove":
+		if len(symbols) == 0 {
+			return watchlistUsage, nil
+		}
+		drop := watchlist.Normalize(watchlist.Watchlist{Symbols: symbols})
+		var kept []string
+		for _, symbol := range current.Symbols {
+			if !drop.Has(symbol) {
+				kept = append(kept, symbol)
+			}
+		}
+		current.Symbols = kept
+	case "delete":
+		if err := s.deleteWatchlist(ctx, name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Deleted watchlist <b>%s</b>.", html.EscapeString(name)), nil
+	case "on", "off":
+		current.Active = action == "on"
+	default:
+		return watchlistUsage, nil
+	}
+
+	if err := s.saveWatchlist(ctx, current); err != nil {
+		return "", err
+	}
+	current, _ = s.watchlists.Get(name)
+	return formatWatchlist(current), nil
+}
+
+func (s *AlertService) formatWatchlists() string {
+	lists := s.watchlists.All()
+	if len(lists) == 0 {
+		return "No watchlists. Create one with /watchlist add &lt;name&gt; &lt;symbols&gt;"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🗂 <b>Watchlists</b>\n")
+	for _, w := range lists {
+		sb.WriteString(formatWatchlist(w) + "\n")
+		if routes := s.routes[w.Name]; len(routes) > 0 {
+			sb.WriteString(fmt.Sprintf("    └ routed to %s\n", strings.Join(routes, ", ")))
+		}
+	}
+	if s.config.WatchlistUnlisted != unlistedAllow {
+		sb.WriteString(fmt.Sprintf("\n<i>Symbols on no active watchlist: %s</i>", s.config.WatchlistUnlisted))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatWatchlist renders "<b>metals</b> (2): GLD, SLV", marking inactive lists
+func formatWatchlist(w watchlist.Watchlist) string {
+	state := ""
+	if !w.Active {
+		state = " <i>(off)</i>"
+	}
+	symbols := strings.Join(w.Symbols, ", ")
+	if symbols == "" {
+		symbols = "–"
+	}
+	return fmt.Sprintf("<b>%s</b>%s (%d): %s", html.EscapeString(w.Name), state, len(w.Symbols), html.EscapeString(symbols))
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/classify"
+	"github.com/trogers1052/alert-service/internal/models"
+)
+
+func decisionEvent(symbol string) *models.DecisionEvent {
+	return &models.DecisionEvent{
+		Timestamp: time.Now(),
+		Data:      models.DecisionData{Symbol: symbol, Signal: models.SignalBuy, Confidence: 0.9},
+	}
+}
+
+func TestIsUnlisted(t *testing.T) {
+	s, _ := newTestService(t, nil, nil)
+	if s.isUnlisted("AAPL") {
+		t.Error("isUnlisted(AAPL) = true with no watchlists")
+	}
+
+	ctx := context.Background()
+	if _, err := s.WatchlistCommand(ctx, 1, []string{"add", "metals", "slv"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if s.isUnlisted("SLV") || !s.isUnlisted("AAPL") {
+		t.Errorf("with metals=SLV: isUnlisted(SLV) = %v, isUnlisted(AAPL) = %v", s.isUnlisted("SLV"), s.isUnlisted("AAPL"))
+	}
+
+	if _, err := s.WatchlistCommand(ctx, 1, []string{"off", "metals"}); err != nil {
+		t.Fatalf("off: %v", err)
+	}
+	if s.isUnlisted("AAPL") {
+		t.Error("isUnlisted(AAPL) = true with every watchlist off")
+	}
+}
+
+func TestUnlistedDecisions(t *testing.T) {
+	env := map[string]string{
+		"WATCHLISTS":            "metals=SLV",
+		"LOW_PRIORITY_CHANNELS": "email",
+	}
+	want := map[string]struct{ telegram, email int }{
+		"allow": {1, 1},
+		"drop":  {0, 0},
+		"route": {0, 1},
+	}
+	for mode, sent := range want {
+		env["WATCHLIST_UNLISTED"] = mode
+		email := &fakeNotifier{name: "email"}
+		s, telegram := newTestService(t, env, nil, email)
+
+		if err := s.HandleDecisionEvent(context.Background(), decisionEvent("AAPL")); err != nil {
+			t.Fatalf("%s: HandleDecisionEvent: %v", mode, err)
+		}
+		if got := len(telegram.messages()); got != sent.telegram {
+			t.Errorf("%s: unlisted AAPL sent %d telegram alerts, want %d", mode, got, sent.telegram)
+		}
+		if got := len(email.messages()); got != sent.email {
+			t.Errorf("%s: unlisted AAPL sent %d email alerts, want %d", mode, got, sent.email)
+		}
+
+		// Listed symbols are never affected
+		if err := s.HandleDecisionEvent(context.Background(), decisionEvent("SLV")); err != nil {
+			t.Fatalf("%s: HandleDecisionEvent: %v", mode, err)
+		}
+		if got := len(telegram.messages()); got != sent.telegram+1 {
+			t.Errorf("%s: listed SLV not sent to telegram", mode)
+		}
+	}
+}
+
+func TestDecisionChannels(t *testing.T) {
+	s, _ := newTestService(t, map[string]string{
+		"WATCHLISTS":            "metals=SLV,GLD;miners=GLD,NEM;tech=AAPL",
+		"WATCHLIST_CHANNELS":    "metals=email;miners=email,sms",
+		"WATCHLIST_UNLISTED":    "route",
+		"LOW_PRIORITY_CHANNELS": "sms",
+	}, nil, &fakeNotifier{name: "email"}, &fakeNotifier{name: "sms"})
+
+	routed := []classify.Tag{{Name: "earnings"}, {Name: "urgent", Channels: []string{"telegram"}}}
+	checks := []struct {
+		symbol      string
+		tags        []classify.Tag
+		lowPriority bool
+		want        []string
+	}{
+		{"SLV", routed, true, []string{"telegram"}},
+		{"SLV", routed[:1], true, []string{"sms"}},
+		{"SLV", nil, false, []string{"email"}},
+		{"GLD", nil, false, []string{"email", "sms"}},
+		{"AAPL", nil, false, []string{"telegram", "email", "sms"}},
+	}
+	for _, c := range checks {
+		if got := s.decisionChannels(c.symbol, c.tags, c.lowPriority); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("decisionChannels(%s, %d tags, %v) = %v, want %v", c.symbol, len(c.tags), c.lowPriority, got, c.want)
+		}
+	}
+}
+
+func TestWatchlistCommand(t *testing.T) {
+	s, _ := newTestService(t, nil, newTestStore(t))
+	ctx := context.Background()
+	run := func(args ...string) string {
+		t.Helper()
+		reply, err := s.WatchlistCommand(ctx, 1, args)
+		if err != nil {
+			t.Fatalf("/watchlist %s: %v", strings.Join(args, " "), err)
+		}
+		return reply
+	}
+
+	if reply := run(); !strings.Contains(reply, "No watchlists") {
+		t.Errorf("empty listing = %q", reply)
+	}
+	if reply := run("add", "Metals", "slv", "gld"); reply != "<b>metals</b> (2): GLD, SLV" {
+		t.Errorf("add = %q", reply)
+	}
+	if reply := run("remove", "metals", "SLV"); reply != "<b>metals</b> (1): GLD" {
+		t.Errorf("remove = %q", reply)
+	}
+	if reply := run("off", "metals"); !strings.Contains(reply, "(off)") {
+		t.Errorf("off = %q", reply)
+	}
+	if w, _ := s.watchlists.Get("metals"); w.Active {
+		t.Error("metals still active after off")
+	}
+	if reply := run("on", "metals"); strings.Contains(reply, "(off)") {
+		t.Errorf("on = %q", reply)
+	}
+
+	// Changes are stored
+	stored, err := s.store.ListWatchlists(ctx)
+	if err != nil {
+		t.Fatalf("ListWatchlists: %v", err)
+	}
+	if len(stored) != 1 || !stored[0].Active || !reflect.DeepEqual(stored[0].Symbols, []string{"GLD"}) {
+		t.Errorf("stored watchlists = %+v, want active metals with GLD", stored)
+	}
+
+	if reply := run("off", "tech"); !strings.Contains(reply, "No watchlist named") {
+		t.Errorf("off on a missing list = %q", reply)
+	}
+	if reply := run("rename", "metals"); reply != watchlistUsage {
+		t.Errorf("unknown action = %q", reply)
+	}
+	if reply := run("add", "metals"); reply != watchlistUsage {
+		t.Errorf("add without symbols = %q", reply)
+	}
+	if reply := run("delete", "metals"); !strings.Contains(reply, "Deleted watchlist") {
+		t.Errorf("delete = %q", reply)
+	}
+	if _, ok := s.watchlists.Get("metals"); ok {
+		t.Error("metals still set after delete")
+	}
+}
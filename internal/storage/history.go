@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/notify"
+)
+
+// Alert kinds recorded in history
+const (
+	KindDecision = "decision"
+	KindRanking  = "ranking"
+	KindRule     = "rule"
+	KindDigest   = "digest"
+	KindReport   = "report"
+)
+
+// Alert statuses recorded in history
+const (
+	StatusSent       = "sent"
+	StatusPartial    = "partial" // delivered on some channels only
+	StatusFailed     = "failed"
+	StatusSuppressed = "suppressed"
+	StatusQueued     = "queued" // held for the quiet hours digest
+)
+
+// Suppression reasons recorded when an alert is not sent
+const (
+	ReasonSignalDisabled  = "signal_disabled"
+	ReasonBelowConfidence = "below_confidence"
+	ReasonCooldown        = "cooldown"
+	ReasonQuietHours      = "quiet_hours"
+	ReasonRankingsOff     = "rankings_disabled"
+	ReasonMuted           = "muted"
+	ReasonOutsideSession  = "outside_session"
+	ReasonDigest          = "digest" // held for a channel in digest mode
+	ReasonNoChange        = "no_change"
+	ReasonNotRanked       = "not_ranked"      // outside the top N of the latest ranking
+	ReasonNotHeld         = "not_held"        // SELL for a symbol without an open position
+	ReasonNotWatched      = "not_watched"     // symbol on no active watchlist
+	ReasonRuleExcluded    = "rule_excluded"   // an excluded decision rule triggered
+	ReasonRuleMissing     = "rule_missing"    // none of the required decision rules triggered
+	ReasonRuleConfidence  = "rule_confidence" // triggered rules were below their minimum confidence
+	ReasonTooFewRules     = "too_few_rules"   // fewer decision rules agreed than required
+)
+
+// AlertRecord is one processed event in the alert_history audit trail
+type AlertRecord struct {
+	ID                int64
+	Kind              string // decision, ranking, rule, digest or report
+	Symbol            string // empty for rankings
+	Signal            string // BUY, SELL, WATCH; ranking signal type
+	Confidence        float64
+	ScaleIn           bool   // BUY decision to add to an existing position
+	RuleID            string // custom rule that fired, for rule alerts
+	Message           string // rendered text, also for suppressed alerts
+	Channels          []string
+	Deliveries        []notify.Delivery
+	Status            string
+	SuppressionReason string
+	EventTime         time.Time // timestamp of the source event
+	CreatedAt         time.Time
+}
+
+// Queue marks the record as held for the digest for the given reason
+func (r *AlertRecord) Queue(reason string) {
+	r.Status = StatusQueued
+	r.SuppressionReason = reason
+}
+
+// Suppress marks the record as not sent for the given reason
+func (r *AlertRecord) Suppress(reason string) {
+	r.Status = StatusSuppressed
+	r.SuppressionReason = reason
+}
+
+// SetDeliveries records the per-channel outcome and derives the overall status
+func (r *AlertRecord) SetDeliveries(deliveries []notify.Delivery) {
+	r.Deliveries = deliveries
+	r.Channels = make([]string, len(deliveries))
+
+	sent := 0
+	for i, d := range deliveries {
+		r.Channels[i] = d.Channel
+		if d.Status == notify.StatusSent {
+			sent++
+		}
+	}
+
+	switch {
+	case len(deliveries) > 0 && sent == len(deliveries):
+		r.Status = StatusSent
+	case sent > 0:
+		r.Status = StatusPartial
+	default:
+		r.Status = StatusFailed
+	}
+}
+
+// HistoryStore persists the alert audit trail
+type HistoryStore interface {
+	RecordAlert(ctx context.Context, record *AlertRecord) error
+	// GetAlert returns the record with the given ID, or nil if there is none
+	GetAlert(ctx context.Context, id int64) (*AlertRecord, error)
+	// ListAlerts returns the records created in [from, to), oldest first
+	ListAlerts(ctx context.Context, from, to time.Time) ([]AlertRecord, error)
+}
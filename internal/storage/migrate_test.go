@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+		tables  []string
+	}{
+		{
+			name: "applies in version order",
+			files: fstest.MapFS{
+				"m/002_add_b.sql":  {Data: []byte(`ALTER TABLE a ADD COLUMN b TEXT;`)},
+				"m/001_create.sql": {Data: []byte(`CREATE TABLE a (id INTEGER);`)},
+				"m/README.md":      {Data: []byte(`ignored`)},
+			},
+			tables: []string{"a"},
+		},
+		{
+			name: "invalid name",
+			files: fstest.MapFS{
+				"m/first.sql": {Data: []byte(`CREATE TABLE a (id INTEGER);`)},
+			},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"m/001_a.sql": {Data: []byte(`CREATE TABLE a (id INTEGER);`)},
+				"m/001_b.sql": {Data: []byte(`CREATE TABLE b (id INTEGER);`)},
+			},
+			wantErr: "duplicate migration version 1",
+		},
+		{
+			name: "failed migration rolls back",
+			files: fstest.MapFS{
+				"m/001_create.sql": {Data: []byte(`CREATE TABLE a (id INTEGER);`)},
+				"m/002_broken.sql": {Data: []byte(`CREATE TABLE c (id INTEGER); NOT SQL;`)},
+			},
+			wantErr: "migration 002_broken failed",
+			tables:  []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			err := Migrate(ctx, db, tt.files, "m")
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Migrate error = %v, want %q", err, tt.wantErr)
+			}
+
+			rows, err := db.QueryContext(ctx,
+				`SELECT name FROM sqlite_master WHERE type = 'table' AND name != 'schema_migrations' ORDER BY name`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			var tables []string
+			for rows.Next() {
+				var name string
+				if err := rows.Scan(&name); err != nil {
+					t.Fatal(err)
+				}
+				tables = append(tables, name)
+			}
+			if strings.Join(tables, ",") != strings.Join(tt.tables, ",") {
+				t.Errorf("tables = %v, want %v", tables, tt.tables)
+			}
+		})
+	}
+}
+
+func TestMigrateSkipsApplied(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	files := fstest.MapFS{"m/001_create.sql": {Data: []byte(`CREATE TABLE a (id INTEGER);`)}}
+
+	if err := Migrate(ctx, db, files, "m"); err != nil {
+		t.Fatal(err)
+	}
+	// Re-running the same CREATE TABLE would fail if it were applied again
+	files["m/002_more.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE b (id INTEGER);`)}
+	if err := Migrate(ctx, db, files, "m"); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	var versions int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&versions); err != nil {
+		t.Fatal(err)
+	}
+	if versions != 2 {
+		t.Errorf("schema_migrations has %d versions, want 2", versions)
+	}
+}
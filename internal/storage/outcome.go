@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// Horizons after a signal at which its forward return is measured
+const (
+	Horizon1h = "1h"
+	Horizon1d = "1d" // one trading day
+	Horizon5d = "5d" // five trading days
+)
+
+// Horizons lists the tracked horizons, shortest first
+var Horizons = []string{Horizon1h, Horizon1d, Horizon5d}
+
+// Outcome tracks how the price moved after a BUY or SELL decision
+type Outcome struct {
+	RecordID   int64 // alert_history record of the decision
+	Symbol     string
+	Signal     string
+	Confidence float64
+	Rules      []string // names of the rules that triggered the decision
+	Alerted    bool     // false when the alert was suppressed
+	EntryPrice float64  // price at alert time
+	AlertedAt  time.Time
+	Returns    map[string]float64 // horizon -> fractional price change, set once the horizon has passed
+}
+
+// Complete reports whether every horizon has a return
+func (o *Outcome) Complete() bool {
+	for _, h := range Horizons {
+		if _, ok := o.Returns[h]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// OutcomeStore persists signal outcomes for hit-rate reporting
+type OutcomeStore interface {
+	// SaveOutcome inserts or replaces the outcome for its record
+	SaveOutcome(ctx context.Context, outcome *Outcome) error
+	// ListPendingOutcomes returns incomplete outcomes alerted since the given time
+	ListPendingOutcomes(ctx context.Context, since time.Time) ([]Outcome, error)
+	// ListOutcomes returns the outcomes alerted in [from, to), oldest first
+	ListOutcomes(ctx context.Context, from, to time.Time) ([]Outcome, error)
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+const historyColumns = `id, kind, symbol, signal, confidence, scale_in, rule_id, message, channels,
+	deliveries, status, suppression_reason, event_time, created_at`
+
+// RecordAlert implements storage.HistoryStore
+func (s *Store) RecordAlert(ctx context.Context, r *storage.AlertRecord) error {
+	deliveries, err := json.Marshal(r.Deliveries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deliveries: %w", err)
+	}
+	if r.Deliveries == nil {
+		deliveries = []byte("[]")
+	}
+
+	var eventTime interface{}
+	if !r.EventTime.IsZero() {
+		eventTime = r.EventTime
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO alert_history (kind, symbol, signal, confidence, scale_in, rule_id, message, channels,
+		                           deliveries, status, suppression_reason, event_time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at`,
+		r.Kind, r.Symbol, r.Signal, r.Confidence, r.ScaleIn, r.RuleID, r.Message, pq.Array(nonNil(r.Channels)),
+		deliveries, r.Status, r.SuppressionReason, eventTime,
+	).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record alert history: %w", err)
+	}
+	return nil
+}
+
+// GetAlert implements storage.HistoryStore
+func (s *Store) GetAlert(ctx context.Context, id int64) (*storage.AlertRecord, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+historyColumns+` FROM alert_history WHERE id = $1`, id)
+	r, err := scanAlert(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert %d: %w", id, err)
+	}
+	return r, nil
+}
+
+// ListAlerts implements storage.HistoryStore
+func (s *Store) ListAlerts(ctx context.Context, from, to time.Time) ([]storage.AlertRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+historyColumns+` FROM alert_history
+		WHERE created_at >= $1 AND created_at < $2 ORDER BY created_at, id`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []storage.AlertRecord
+	for rows.Next() {
+		r, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert history: %w", err)
+		}
+		records = append(records, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query alert history: %w", err)
+	}
+	return records, nil
+}
+
+// scanAlert reads one alert_history row selected with historyColumns
+func scanAlert(row interface{ Scan(...interface{}) error }) (*storage.AlertRecord, error) {
+	var r storage.AlertRecord
+	var deliveries []byte
+	var eventTime sql.NullTime
+	err := row.Scan(&r.ID, &r.Kind, &r.Symbol, &r.Signal, &r.Confidence, &r.ScaleIn, &r.RuleID, &r.Message,
+		pq.Array(&r.Channels), &deliveries, &r.Status, &r.SuppressionReason, &eventTime, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(deliveries, &r.Deliveries); err != nil {
+		return nil, fmt.Errorf("alert %d has invalid deliveries: %w", r.ID, err)
+	}
+	r.EventTime = eventTime.Time
+	return &r, nil
+}
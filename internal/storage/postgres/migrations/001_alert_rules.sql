@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id               TEXT PRIMARY KEY,
+    name             TEXT NOT NULL DEFAULT '',
+    rule_type        TEXT NOT NULL DEFAULT 'EXPRESSION',
+    parameters       JSONB NOT NULL DEFAULT '{}',
+    symbols          TEXT[] NOT NULL DEFAULT '{}',
+    symbol_group     TEXT NOT NULL DEFAULT '',
+    condition        TEXT NOT NULL DEFAULT '',
+    message          TEXT NOT NULL DEFAULT '',
+    trigger_mode     TEXT NOT NULL DEFAULT 'level',
+    hysteresis       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    channels         TEXT[] NOT NULL DEFAULT '{}',
+    cooldown_minutes INTEGER NOT NULL DEFAULT 0,
+    enabled          BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS alert_rule_groups (
+    name       TEXT PRIMARY KEY,
+    symbols    TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Notify listeners whenever rules or groups change so they reload without a restart
+CREATE OR REPLACE FUNCTION notify_alert_rules_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('alert_rules_changed', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS alert_rules_changed ON alert_rules;
+CREATE TRIGGER alert_rules_changed
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON alert_rules
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_alert_rules_changed();
+
+DROP TRIGGER IF EXISTS alert_rule_groups_changed ON alert_rule_groups;
+CREATE TRIGGER alert_rule_groups_changed
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON alert_rule_groups
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_alert_rules_changed();
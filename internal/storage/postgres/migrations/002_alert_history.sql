@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS alert_history (
+    id                 BIGSERIAL PRIMARY KEY,
+    kind               TEXT NOT NULL,
+    symbol             TEXT NOT NULL DEFAULT '',
+    signal             TEXT NOT NULL DEFAULT '',
+    confidence         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rule_id            TEXT NOT NULL DEFAULT '',
+    message            TEXT NOT NULL DEFAULT '',
+    channels           TEXT[] NOT NULL DEFAULT '{}',
+    deliveries         JSONB NOT NULL DEFAULT '[]',
+    status             TEXT NOT NULL,
+    suppression_reason TEXT NOT NULL DEFAULT '',
+    event_time         TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS alert_history_symbol_created_idx ON alert_history (symbol, created_at DESC);
+CREATE INDEX IF NOT EXISTS alert_history_created_idx ON alert_history (created_at DESC);
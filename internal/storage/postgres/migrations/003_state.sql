@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS alert_cooldowns (
+    cooldown_key TEXT PRIMARY KEY,
+    last_alert   TIMESTAMPTZ NOT NULL,
+    signal       TEXT NOT NULL DEFAULT '',
+    confidence   DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS symbol_mutes (
+    symbol     TEXT PRIMARY KEY,
+    until      TIMESTAMPTZ NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS subscribers (
+    chat_id     BIGINT PRIMARY KEY,
+    name        TEXT NOT NULL DEFAULT '',
+    enabled     BOOLEAN NOT NULL DEFAULT TRUE,
+    quiet_hours TEXT NOT NULL DEFAULT '',
+    timezone    TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS alert_queue (
+    id         BIGSERIAL PRIMARY KEY,
+    chat_id    BIGINT NOT NULL DEFAULT 0,
+    kind       TEXT NOT NULL,
+    symbol     TEXT NOT NULL DEFAULT '',
+    signal     TEXT NOT NULL DEFAULT '',
+    confidence DOUBLE PRECISION NOT NULL DEFAULT 0,
+    message    TEXT NOT NULL DEFAULT '',
+    event_time TIMESTAMPTZ,
+    queued_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
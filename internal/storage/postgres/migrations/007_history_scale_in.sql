@@ -0,0 +1 @@
+ALTER TABLE alert_history ADD COLUMN IF NOT EXISTS scale_in BOOLEAN NOT NULL DEFAULT false;
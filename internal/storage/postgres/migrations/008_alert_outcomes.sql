@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS alert_outcomes (
+    record_id   BIGINT PRIMARY KEY,
+    symbol      TEXT NOT NULL,
+    signal      TEXT NOT NULL,
+    confidence  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rules       TEXT[] NOT NULL DEFAULT '{}',
+    alerted     BOOLEAN NOT NULL DEFAULT false,
+    entry_price DOUBLE PRECISION NOT NULL,
+    alerted_at  TIMESTAMPTZ NOT NULL,
+    return_1h   DOUBLE PRECISION,
+    return_1d   DOUBLE PRECISION,
+    return_5d   DOUBLE PRECISION
+);
+
+CREATE INDEX IF NOT EXISTS alert_outcomes_alerted_at_idx ON alert_outcomes (alerted_at);
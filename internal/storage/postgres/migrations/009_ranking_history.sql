@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS ranking_history (
+    id          BIGSERIAL PRIMARY KEY,
+    signal_type TEXT NOT NULL,
+    symbol      TEXT NOT NULL,
+    rank        INTEGER NOT NULL,
+    score       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    confidence  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ranked_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ranking_history_symbol_idx ON ranking_history (symbol, signal_type, ranked_at);
+CREATE INDEX IF NOT EXISTS ranking_history_ranked_at_idx ON ranking_history (signal_type, ranked_at);
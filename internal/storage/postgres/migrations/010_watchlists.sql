@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS watchlists (
+    name       TEXT PRIMARY KEY,
+    symbols    TEXT[] NOT NULL DEFAULT '{}',
+    active     BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+const outcomeColumns = `record_id, symbol, signal, confidence, rules, alerted, entry_price, alerted_at,
+	return_1h, return_1d, return_5d`
+
+// SaveOutcome implements storage.OutcomeStore
+func (s *Store) SaveOutcome(ctx context.Context, o *storage.Outcome) error {
+	r1h, r1d, r5d := outcomeReturns(o)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_outcomes (`+outcomeColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (record_id) DO UPDATE SET
+			return_1h = EXCLUDED.return_1h,
+			return_1d = EXCLUDED.return_1d,
+			return_5d = EXCLUDED.return_5d`,
+		o.RecordID, o.Symbol, o.Signal, o.Confidence, pq.Array(nonNil(o.Rules)), o.Alerted, o.EntryPrice, o.AlertedAt,
+		r1h, r1d, r5d)
+	if err != nil {
+		return fmt.Errorf("failed to save outcome for alert %d: %w", o.RecordID, err)
+	}
+	return nil
+}
+
+// ListPendingOutcomes implements storage.OutcomeStore
+func (s *Store) ListPendingOutcomes(ctx context.Context, since time.Time) ([]storage.Outcome, error) {
+	return s.listOutcomes(ctx, `
+		SELECT `+outcomeColumns+` FROM alert_outcomes
+		WHERE alerted_at >= $1 AND (return_1h IS NULL OR return_1d IS NULL OR return_5d IS NULL)
+		ORDER BY alerted_at, record_id`, since)
+}
+
+// ListOutcomes implements storage.OutcomeStore
+func (s *Store) ListOutcomes(ctx context.Context, from, to time.Time) ([]storage.Outcome, error) {
+	return s.listOutcomes(ctx, `
+		SELECT `+outcomeColumns+` FROM alert_outcomes
+		WHERE alerted_at >= $1 AND alerted_at < $2 ORDER BY alerted_at, record_id`, from, to)
+}
+
+func (s *Store) listOutcomes(ctx context.Context, query string, args ...interface{}) ([]storage.Outcome, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var outcomes []storage.Outcome
+	for rows.Next() {
+		var o storage.Outcome
+		var r1h, r1d, r5d sql.NullFloat64
+		if err := rows.Scan(&o.RecordID, &o.Symbol, &o.Signal, &o.Confidence, pq.Array(&o.Rules), &o.Alerted,
+			&o.EntryPrice, &o.AlertedAt, &r1h, &r1d, &r5d); err != nil {
+			return nil, fmt.Errorf("failed to scan outcome: %w", err)
+		}
+		setOutcomeReturns(&o, r1h, r1d, r5d)
+		outcomes = append(outcomes, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query outcomes: %w", err)
+	}
+	return outcomes, nil
+}
+
+// outcomeReturns maps an outcome's returns to the nullable return columns
+func outcomeReturns(o *storage.Outcome) (r1h, r1d, r5d sql.NullFloat64) {
+	get := func(h string) sql.NullFloat64 {
+		v, ok := o.Returns[h]
+		return sql.NullFloat64{Float64: v, Valid: ok}
+	}
+	return get(storage.Horizon1h), get(storage.Horizon1d), get(storage.Horizon5d)
+}
+
+func setOutcomeReturns(o *storage.Outcome, r1h, r1d, r5d sql.NullFloat64) {
+	o.Returns = make(map[string]float64)
+	for h, v := range map[string]sql.NullFloat64{storage.Horizon1h: r1h, storage.Horizon1d: r1d, storage.Horizon5d: r5d} {
+		if v.Valid {
+			o.Returns[h] = v.Float64
+		}
+	}
+}
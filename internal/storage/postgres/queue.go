@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// EnqueueAlert implements storage.QueueStore
+func (s *Store) EnqueueAlert(ctx context.Context, a *storage.QueuedAlert) error {
+	var eventTime interface{}
+	if !a.EventTime.IsZero() {
+		eventTime = a.EventTime
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO alert_queue (channel, target, chat_id, record_id, kind, symbol, signal, confidence, message, event_time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, queued_at`,
+		a.Channel, a.Target, a.ChatID, a.RecordID, a.Kind, a.Symbol, a.Signal, a.Confidence, a.Message, eventTime,
+	).Scan(&a.ID, &a.QueuedAt)
+	if err != nil {
+		return fmt.Errorf("failed to queue alert: %w", err)
+	}
+	return nil
+}
+
+// ListQueuedAlerts implements storage.QueueStore
+func (s *Store) ListQueuedAlerts(ctx context.Context, channel string) ([]storage.QueuedAlert, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, channel, target, chat_id, record_id, kind, symbol, signal, confidence, message, event_time, queued_at
+		FROM alert_queue WHERE channel = $1 ORDER BY queued_at, id`, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert queue: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []storage.QueuedAlert
+	for rows.Next() {
+		var a storage.QueuedAlert
+		var eventTime sql.NullTime
+		if err := rows.Scan(&a.ID, &a.Channel, &a.Target, &a.ChatID, &a.RecordID, &a.Kind, &a.Symbol, &a.Signal, &a.Confidence, &a.Message,
+			&eventTime, &a.QueuedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan queued alert: %w", err)
+		}
+		a.EventTime = eventTime.Time
+		alerts = append(alerts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query alert queue: %w", err)
+	}
+	return alerts, nil
+}
+
+// DeleteQueuedAlerts implements storage.QueueStore
+func (s *Store) DeleteQueuedAlerts(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM alert_queue WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to delete queued alerts: %w", err)
+	}
+	return nil
+}
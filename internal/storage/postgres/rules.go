@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/trogers1052/alert-service/internal/rules"
+)
+
+// rulesChannel is the NOTIFY channel raised by the alert_rules triggers
+const rulesChannel = "alert_rules_changed"
+
+// LoadRules implements rules.Source
+func (s *Store) LoadRules(ctx context.Context) (*rules.RuleSet, error) {
+	set := &rules.RuleSet{Groups: make(map[string][]string)}
+
+	groupRows, err := s.db.QueryContext(ctx, `SELECT name, symbols FROM alert_rule_groups`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule groups: %w", err)
+	}
+	defer groupRows.Close()
+	for groupRows.Next() {
+		var name string
+		var symbols []string
+		if err := groupRows.Scan(&name, pq.Array(&symbols)); err != nil {
+			return nil, fmt.Errorf("failed to scan rule group: %w", err)
+		}
+		set.Groups[name] = symbols
+	}
+	if err := groupRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rule groups: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, rule_type, parameters, symbols, symbol_group, condition, message,
+		       trigger_mode, hysteresis, channels, cooldown_minutes, enabled
+		FROM alert_rules
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r rules.Rule
+		var params []byte
+		var enabled bool
+		if err := rows.Scan(&r.ID, &r.Name, &r.Type, &params, pq.Array(&r.Symbols), &r.Group,
+			&r.Condition, &r.Message, &r.Trigger, &r.Hysteresis, pq.Array(&r.Channels),
+			&r.CooldownMinutes, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan rule: %w", err)
+		}
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &r.Params); err != nil {
+				return nil, fmt.Errorf("rule %q has invalid parameters: %w", r.ID, err)
+			}
+		}
+		r.Enabled = &enabled
+		set.Rules = append(set.Rules, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rules: %w", err)
+	}
+
+	return set, nil
+}
+
+// SaveRule inserts or updates a rule
+func (s *Store) SaveRule(ctx context.Context, r rules.Rule) error {
+	params, err := json.Marshal(r.Params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule parameters: %w", err)
+	}
+	if r.Params == nil {
+		params = []byte("{}")
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO alert_rules (id, name, rule_type, parameters, symbols, symbol_group, condition, message,
+		                         trigger_mode, hysteresis, channels, cooldown_minutes, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			rule_type = EXCLUDED.rule_type,
+			parameters = EXCLUDED.parameters,
+			symbols = EXCLUDED.symbols,
+			symbol_group = EXCLUDED.symbol_group,
+			condition = EXCLUDED.condition,
+			message = EXCLUDED.message,
+			trigger_mode = EXCLUDED.trigger_mode,
+			hysteresis = EXCLUDED.hysteresis,
+			channels = EXCLUDED.channels,
+			cooldown_minutes = EXCLUDED.cooldown_minutes,
+			enabled = EXCLUDED.enabled,
+			updated_at = NOW()`,
+		r.ID, r.Name, r.RuleType(), params, pq.Array(nonNil(r.Symbols)), r.Group, r.Condition, r.Message,
+		r.TriggerMode(), r.Hysteresis, pq.Array(nonNil(r.Channels)), r.CooldownMinutes, r.IsEnabled())
+	if err != nil {
+		return fmt.Errorf("failed to save rule %q: %w", r.ID, err)
+	}
+	return nil
+}
+
+// DeleteRule removes a rule
+func (s *Store) DeleteRule(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete rule %q: %w", id, err)
+	}
+	return nil
+}
+
+// ListenRuleChanges subscribes to rule change notifications. The returned
+// channel receives a value after every change to alert_rules or
+// alert_rule_groups and after the listener reconnects, since notifications
+// may have been missed while disconnected. It is closed when ctx is done.
+func (s *Store) ListenRuleChanges(ctx context.Context) (<-chan struct{}, error) {
+	listener := pq.NewListener(s.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Postgres rule listener: %v", err)
+		}
+	})
+	if err := listener.Listen(rulesChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", rulesChannel, err)
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer listener.Close()
+
+		// Notifications carry no payload we need; a nil notification
+		// signals a reconnect, which also warrants a reload
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-listener.Notify:
+			case <-time.After(90 * time.Second):
+				go listener.Ping()
+				continue
+			}
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// SaveRuleGroup inserts or replaces a named symbol group
+func (s *Store) SaveRuleGroup(ctx context.Context, name string, symbols []string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_rule_groups (name, symbols) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET symbols = EXCLUDED.symbols, updated_at = NOW()`,
+		name, pq.Array(nonNil(symbols)))
+	if err != nil {
+		return fmt.Errorf("failed to save rule group %q: %w", name, err)
+	}
+	return nil
+}
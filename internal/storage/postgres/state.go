@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// LoadCooldowns implements storage.CooldownStore
+func (s *Store) LoadCooldowns(ctx context.Context) ([]storage.Cooldown, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT cooldown_key, last_alert, signal, confidence, level FROM alert_cooldowns`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cooldowns: %w", err)
+	}
+	defer rows.Close()
+
+	var cooldowns []storage.Cooldown
+	for rows.Next() {
+		var c storage.Cooldown
+		if err := rows.Scan(&c.Key, &c.LastAlert, &c.Signal, &c.Confidence, &c.Level); err != nil {
+			return nil, fmt.Errorf("failed to scan cooldown: %w", err)
+		}
+		cooldowns = append(cooldowns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query cooldowns: %w", err)
+	}
+	return cooldowns, nil
+}
+
+// SaveCooldown implements storage.CooldownStore
+func (s *Store) SaveCooldown(ctx context.Context, c storage.Cooldown) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_cooldowns (cooldown_key, last_alert, signal, confidence, level) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (cooldown_key) DO UPDATE SET
+			last_alert = EXCLUDED.last_alert,
+			signal = EXCLUDED.signal,
+			confidence = EXCLUDED.confidence,
+			level = EXCLUDED.level`,
+		c.Key, c.LastAlert, c.Signal, c.Confidence, c.Level)
+	if err != nil {
+		return fmt.Errorf("failed to save cooldown %q: %w", c.Key, err)
+	}
+	return nil
+}
+
+// DeleteCooldownsBefore implements storage.CooldownStore
+func (s *Store) DeleteCooldownsBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM alert_cooldowns WHERE last_alert < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire cooldowns: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetMute implements storage.MuteStore
+func (s *Store) GetMute(ctx context.Context, symbol string, now time.Time) (*storage.Mute, error) {
+	var m storage.Mute
+	err := s.db.QueryRowContext(ctx, `
+		SELECT symbol, until, reason, created_at FROM symbol_mutes WHERE symbol = $1 AND until > $2`,
+		strings.ToUpper(symbol), now).Scan(&m.Symbol, &m.Until, &m.Reason, &m.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mute for %s: %w", symbol, err)
+	}
+	return &m, nil
+}
+
+// ListMutes implements storage.MuteStore
+func (s *Store) ListMutes(ctx context.Context, now time.Time) ([]storage.Mute, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT symbol, until, reason, created_at FROM symbol_mutes WHERE until > $1 ORDER BY symbol`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mutes: %w", err)
+	}
+	defer rows.Close()
+
+	var mutes []storage.Mute
+	for rows.Next() {
+		var m storage.Mute
+		if err := rows.Scan(&m.Symbol, &m.Until, &m.Reason, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan mute: %w", err)
+		}
+		mutes = append(mutes, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query mutes: %w", err)
+	}
+	return mutes, nil
+}
+
+// SaveMute implements storage.MuteStore
+func (s *Store) SaveMute(ctx context.Context, m storage.Mute) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO symbol_mutes (symbol, until, reason) VALUES ($1, $2, $3)
+		ON CONFLICT (symbol) DO UPDATE SET until = EXCLUDED.until, reason = EXCLUDED.reason`,
+		strings.ToUpper(m.Symbol), m.Until, m.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to save mute for %s: %w", m.Symbol, err)
+	}
+	return nil
+}
+
+// DeleteMute implements storage.MuteStore
+func (s *Store) DeleteMute(ctx context.Context, symbol string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM symbol_mutes WHERE symbol = $1`, strings.ToUpper(symbol)); err != nil {
+		return fmt.Errorf("failed to delete mute for %s: %w", symbol, err)
+	}
+	return nil
+}
+
+// ListSubscribers implements storage.SubscriberStore
+func (s *Store) ListSubscribers(ctx context.Context) ([]storage.Subscriber, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chat_id, name, enabled, quiet_hours, timezone, created_at FROM subscribers ORDER BY chat_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []storage.Subscriber
+	for rows.Next() {
+		var sub storage.Subscriber
+		if err := rows.Scan(&sub.ChatID, &sub.Name, &sub.Enabled, &sub.QuietHours, &sub.Timezone, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		subscribers = append(subscribers, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query subscribers: %w", err)
+	}
+	return subscribers, nil
+}
+
+// SaveSubscriber implements storage.SubscriberStore
+func (s *Store) SaveSubscriber(ctx context.Context, sub storage.Subscriber) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO subscribers (chat_id, name, enabled, quiet_hours, timezone) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chat_id) DO UPDATE SET
+			name = EXCLUDED.name,
+			enabled = EXCLUDED.enabled,
+			quiet_hours = EXCLUDED.quiet_hours,
+			timezone = EXCLUDED.timezone`,
+		sub.ChatID, sub.Name, sub.Enabled, sub.QuietHours, sub.Timezone)
+	if err != nil {
+		return fmt.Errorf("failed to save subscriber %d: %w", sub.ChatID, err)
+	}
+	return nil
+}
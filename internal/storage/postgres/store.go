@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Config holds PostgreSQL connection settings
+type Config struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+}
+
+// DSN returns the lib/pq connection string
+func (c Config) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
+}
+
+// Store is the PostgreSQL-backed persistence layer
+type Store struct {
+	db  *sql.DB
+	dsn string
+}
+
+var _ storage.Store = (*Store)(nil)
+
+// Open connects to PostgreSQL and verifies the connection
+func Open(ctx context.Context, cfg Config) (*Store, error) {
+	dsn := cfg.DSN()
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	db.SetMaxOpenConns(5)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres at %s:%d: %w", cfg.Host, cfg.Port, err)
+	}
+
+	return &Store{db: db, dsn: dsn}, nil
+}
+
+// Migrate applies pending schema migrations
+func (s *Store) Migrate(ctx context.Context) error {
+	return storage.Migrate(ctx, s.db, migrations, "migrations")
+}
+
+// Close closes the database connection pool
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/trogers1052/alert-service/internal/watchlist"
+)
+
+// ListWatchlists implements storage.WatchlistStore
+func (s *Store) ListWatchlists(ctx context.Context) ([]watchlist.Watchlist, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, symbols, active, updated_at FROM watchlists ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	var lists []watchlist.Watchlist
+	for rows.Next() {
+		var w watchlist.Watchlist
+		if err := rows.Scan(&w.Name, pq.Array(&w.Symbols), &w.Active, &w.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist: %w", err)
+		}
+		lists = append(lists, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query watchlists: %w", err)
+	}
+	return lists, nil
+}
+
+// SaveWatchlist implements storage.WatchlistStore
+func (s *Store) SaveWatchlist(ctx context.Context, w watchlist.Watchlist) error {
+	w = watchlist.Normalize(w)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO watchlists (name, symbols, active) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET symbols = EXCLUDED.symbols, active = EXCLUDED.active, updated_at = NOW()`,
+		w.Name, pq.Array(nonNil(w.Symbols)), w.Active)
+	if err != nil {
+		return fmt.Errorf("failed to save watchlist %q: %w", w.Name, err)
+	}
+	return nil
+}
+
+// DeleteWatchlist implements storage.WatchlistStore
+func (s *Store) DeleteWatchlist(ctx context.Context, name string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM watchlists WHERE name = $1`, watchlist.NormalizeName(name)); err != nil {
+		return fmt.Errorf("failed to delete watchlist %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// QueuedAlert is an alert held back for the next digest, either by quiet
+// hours or because its channel is in digest mode
+type QueuedAlert struct {
+	ID         int64
+	Channel    string // digest channel; empty for the quiet hours digest
+	Target     string // channel a quiet hours alert is held for
+	ChatID     int64  // Telegram chat a quiet hours alert is held for, 0 for other channels
+	RecordID   int64  // alert_history record with the full alert, 0 if unknown
+	Kind       string // decision, ranking or rule
+	Symbol     string // empty for rankings
+	Signal     string // rule ID for rule alerts
+	Confidence float64
+	Message    string // rendered alert text
+	EventTime  time.Time
+	QueuedAt   time.Time
+}
+
+// QueueStore persists alerts held for digests so they survive a restart
+// until the digest is delivered
+type QueueStore interface {
+	EnqueueAlert(ctx context.Context, alert *QueuedAlert) error
+	// ListQueuedAlerts returns the alerts queued for a channel, oldest first
+	ListQueuedAlerts(ctx context.Context, channel string) ([]QueuedAlert, error)
+	DeleteQueuedAlerts(ctx context.Context, ids []int64) error
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// RankingEntry is one symbol's position in a ranking
+type RankingEntry struct {
+	SignalType string
+	Symbol     string
+	Rank       int
+	Score      float64
+	Confidence float64
+	RankedAt   time.Time // timestamp of the ranking, shared by all its entries
+}
+
+// RankingStore persists every ranking so rank and score can be followed
+// over time
+type RankingStore interface {
+	// SaveRankings stores the entries of one ranking
+	SaveRankings(ctx context.Context, entries []RankingEntry) error
+	// LatestRankings returns the most recent ranking of a signal type, best first
+	LatestRankings(ctx context.Context, signalType string) ([]RankingEntry, error)
+	// SymbolRankings returns a symbol's entries in rankings of a signal type
+	// since the given time, oldest first
+	SymbolRankings(ctx context.Context, signalType, symbol string, since time.Time) ([]RankingEntry, error)
+	DeleteRankingsBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+const historyColumns = `id, kind, symbol, signal, confidence, scale_in, rule_id, message, channels,
+	deliveries, status, suppression_reason, event_time, created_at`
+
+// RecordAlert implements storage.HistoryStore
+func (s *Store) RecordAlert(ctx context.Context, r *storage.AlertRecord) error {
+	channels, err := marshalJSON(r.Channels, "[]")
+	if err != nil {
+		return fmt.Errorf("failed to marshal channels: %w", err)
+	}
+	deliveries, err := marshalJSON(r.Deliveries, "[]")
+	if err != nil {
+		return fmt.Errorf("failed to marshal deliveries: %w", err)
+	}
+
+	var eventTime interface{}
+	if !r.EventTime.IsZero() {
+		eventTime = formatTime(r.EventTime)
+	}
+	createdAt := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_history (kind, symbol, signal, confidence, scale_in, rule_id, message, channels,
+		                           deliveries, status, suppression_reason, event_time, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.Kind, r.Symbol, r.Signal, r.Confidence, r.ScaleIn, r.RuleID, r.Message, channels,
+		deliveries, r.Status, r.SuppressionReason, eventTime, formatTime(createdAt))
+	if err != nil {
+		return fmt.Errorf("failed to record alert history: %w", err)
+	}
+
+	if r.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to record alert history: %w", err)
+	}
+	r.CreatedAt = createdAt
+	return nil
+}
+
+// GetAlert implements storage.HistoryStore
+func (s *Store) GetAlert(ctx context.Context, id int64) (*storage.AlertRecord, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+historyColumns+` FROM alert_history WHERE id = ?`, id)
+	r, err := scanAlert(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert %d: %w", id, err)
+	}
+	return r, nil
+}
+
+// ListAlerts implements storage.HistoryStore
+func (s *Store) ListAlerts(ctx context.Context, from, to time.Time) ([]storage.AlertRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+historyColumns+` FROM alert_history
+		WHERE created_at >= ? AND created_at < ? ORDER BY created_at, id`, formatTime(from), formatTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []storage.AlertRecord
+	for rows.Next() {
+		r, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert history: %w", err)
+		}
+		records = append(records, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query alert history: %w", err)
+	}
+	return records, nil
+}
+
+// scanAlert reads one alert_history row selected with historyColumns
+func scanAlert(row interface{ Scan(...interface{}) error }) (*storage.AlertRecord, error) {
+	var r storage.AlertRecord
+	var channels, deliveries, createdAt string
+	var eventTime sql.NullString
+	err := row.Scan(&r.ID, &r.Kind, &r.Symbol, &r.Signal, &r.Confidence, &r.ScaleIn, &r.RuleID, &r.Message,
+		&channels, &deliveries, &r.Status, &r.SuppressionReason, &eventTime, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(channels), &r.Channels); err != nil {
+		return nil, fmt.Errorf("alert %d has invalid channels: %w", r.ID, err)
+	}
+	if err := json.Unmarshal([]byte(deliveries), &r.Deliveries); err != nil {
+		return nil, fmt.Errorf("alert %d has invalid deliveries: %w", r.ID, err)
+	}
+	if r.EventTime, err = parseTime(eventTime.String); err != nil {
+		return nil, fmt.Errorf("alert %d has invalid event time: %w", r.ID, err)
+	}
+	if r.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("alert %d has invalid creation time: %w", r.ID, err)
+	}
+	return &r, nil
+}
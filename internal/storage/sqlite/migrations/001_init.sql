@@ -0,0 +1,65 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id               TEXT PRIMARY KEY,
+    name             TEXT NOT NULL DEFAULT '',
+    rule_type        TEXT NOT NULL DEFAULT 'EXPRESSION',
+    parameters       TEXT NOT NULL DEFAULT '{}', -- JSON object
+    symbols          TEXT NOT NULL DEFAULT '[]', -- JSON array
+    symbol_group     TEXT NOT NULL DEFAULT '',
+    condition        TEXT NOT NULL DEFAULT '',
+    message          TEXT NOT NULL DEFAULT '',
+    trigger_mode     TEXT NOT NULL DEFAULT 'level',
+    hysteresis       REAL NOT NULL DEFAULT 0,
+    channels         TEXT NOT NULL DEFAULT '[]', -- JSON array
+    cooldown_minutes INTEGER NOT NULL DEFAULT 0,
+    enabled          INTEGER NOT NULL DEFAULT 1,
+    created_at       TEXT NOT NULL,
+    updated_at       TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS alert_rule_groups (
+    name       TEXT PRIMARY KEY,
+    symbols    TEXT NOT NULL DEFAULT '[]', -- JSON array
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS alert_history (
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind               TEXT NOT NULL,
+    symbol             TEXT NOT NULL DEFAULT '',
+    signal             TEXT NOT NULL DEFAULT '',
+    confidence         REAL NOT NULL DEFAULT 0,
+    rule_id            TEXT NOT NULL DEFAULT '',
+    message            TEXT NOT NULL DEFAULT '',
+    channels           TEXT NOT NULL DEFAULT '[]', -- JSON array
+    deliveries         TEXT NOT NULL DEFAULT '[]', -- JSON array
+    status             TEXT NOT NULL,
+    suppression_reason TEXT NOT NULL DEFAULT '',
+    event_time         TEXT,
+    created_at         TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS alert_history_symbol_created_idx ON alert_history (symbol, created_at);
+CREATE INDEX IF NOT EXISTS alert_history_created_idx ON alert_history (created_at);
+
+CREATE TABLE IF NOT EXISTS alert_cooldowns (
+    cooldown_key TEXT PRIMARY KEY,
+    last_alert   TEXT NOT NULL,
+    signal       TEXT NOT NULL DEFAULT '',
+    confidence   REAL NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS symbol_mutes (
+    symbol     TEXT PRIMARY KEY,
+    until      TEXT NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS subscribers (
+    chat_id     INTEGER PRIMARY KEY,
+    name        TEXT NOT NULL DEFAULT '',
+    enabled     INTEGER NOT NULL DEFAULT 1,
+    quiet_hours TEXT NOT NULL DEFAULT '',
+    timezone    TEXT NOT NULL DEFAULT '',
+    created_at  TEXT NOT NULL
+);
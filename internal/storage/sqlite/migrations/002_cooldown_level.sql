@@ -0,0 +1 @@
+ALTER TABLE alert_cooldowns ADD COLUMN level INTEGER NOT NULL DEFAULT 0;
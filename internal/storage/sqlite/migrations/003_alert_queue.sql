@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS alert_queue (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id    INTEGER NOT NULL DEFAULT 0,
+    kind       TEXT NOT NULL,
+    symbol     TEXT NOT NULL DEFAULT '',
+    signal     TEXT NOT NULL DEFAULT '',
+    confidence REAL NOT NULL DEFAULT 0,
+    message    TEXT NOT NULL DEFAULT '',
+    event_time TEXT,
+    queued_at  TEXT NOT NULL
+);
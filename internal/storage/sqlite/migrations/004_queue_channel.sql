@@ -0,0 +1,5 @@
+ALTER TABLE alert_queue ADD COLUMN channel TEXT NOT NULL DEFAULT '';
+ALTER TABLE alert_queue ADD COLUMN record_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alert_queue ADD COLUMN target TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS alert_queue_channel_idx ON alert_queue (channel, queued_at);
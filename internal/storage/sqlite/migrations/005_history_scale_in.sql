@@ -0,0 +1 @@
+ALTER TABLE alert_history ADD COLUMN scale_in INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS alert_outcomes (
+    record_id   INTEGER PRIMARY KEY,
+    symbol      TEXT NOT NULL,
+    signal      TEXT NOT NULL,
+    confidence  REAL NOT NULL DEFAULT 0,
+    rules       TEXT NOT NULL DEFAULT '[]', -- JSON array
+    alerted     INTEGER NOT NULL DEFAULT 0,
+    entry_price REAL NOT NULL,
+    alerted_at  TEXT NOT NULL,
+    return_1h   REAL,
+    return_1d   REAL,
+    return_5d   REAL
+);
+
+CREATE INDEX IF NOT EXISTS alert_outcomes_alerted_at_idx ON alert_outcomes (alerted_at);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS watchlists (
+    name       TEXT PRIMARY KEY,
+    symbols    TEXT NOT NULL DEFAULT '[]', -- JSON array
+    active     INTEGER NOT NULL DEFAULT 1,
+    updated_at TEXT NOT NULL
+);
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+const outcomeColumns = `record_id, symbol, signal, confidence, rules, alerted, entry_price, alerted_at,
+	return_1h, return_1d, return_5d`
+
+// SaveOutcome implements storage.OutcomeStore
+func (s *Store) SaveOutcome(ctx context.Context, o *storage.Outcome) error {
+	rules, err := marshalJSON(o.Rules, "[]")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
+	r1h, r1d, r5d := outcomeReturns(o)
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO alert_outcomes (`+outcomeColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (record_id) DO UPDATE SET
+			return_1h = excluded.return_1h,
+			return_1d = excluded.return_1d,
+			return_5d = excluded.return_5d`,
+		o.RecordID, o.Symbol, o.Signal, o.Confidence, rules, o.Alerted, o.EntryPrice, formatTime(o.AlertedAt),
+		r1h, r1d, r5d)
+	if err != nil {
+		return fmt.Errorf("failed to save outcome for alert %d: %w", o.RecordID, err)
+	}
+	return nil
+}
+
+// ListPendingOutcomes implements storage.OutcomeStore
+func (s *Store) ListPendingOutcomes(ctx context.Context, since time.Time) ([]storage.Outcome, error) {
+	return s.listOutcomes(ctx, `
+		SELECT `+outcomeColumns+` FROM alert_outcomes
+		WHERE alerted_at >= ? AND (return_1h IS NULL OR return_1d IS NULL OR return_5d IS NULL)
+		ORDER BY alerted_at, record_id`, formatTime(since))
+}
+
+// ListOutcomes implements storage.OutcomeStore
+func (s *Store) ListOutcomes(ctx context.Context, from, to time.Time) ([]storage.Outcome, error) {
+	return s.listOutcomes(ctx, `
+		SELECT `+outcomeColumns+` FROM alert_outcomes
+		WHERE alerted_at >= ? AND alerted_at < ? ORDER BY alerted_at, record_id`, formatTime(from), formatTime(to))
+}
+
+func (s *Store) listOutcomes(ctx context.Context, query string, args ...interface{}) ([]storage.Outcome, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var outcomes []storage.Outcome
+	for rows.Next() {
+		var o storage.Outcome
+		var rules, alertedAt string
+		var r1h, r1d, r5d sql.NullFloat64
+		if err := rows.Scan(&o.RecordID, &o.Symbol, &o.Signal, &o.Confidence, &rules, &o.Alerted,
+			&o.EntryPrice, &alertedAt, &r1h, &r1d, &r5d); err != nil {
+			return nil, fmt.Errorf("failed to scan outcome: %w", err)
+		}
+		if err := json.Unmarshal([]byte(rules), &o.Rules); err != nil {
+			return nil, fmt.Errorf("outcome %d has invalid rules: %w", o.RecordID, err)
+		}
+		if o.AlertedAt, err = parseTime(alertedAt); err != nil {
+			return nil, fmt.Errorf("outcome %d has invalid alert time: %w", o.RecordID, err)
+		}
+		setOutcomeReturns(&o, r1h, r1d, r5d)
+		outcomes = append(outcomes, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query outcomes: %w", err)
+	}
+	return outcomes, nil
+}
+
+// outcomeReturns maps an outcome's returns to the nullable return columns
+func outcomeReturns(o *storage.Outcome) (r1h, r1d, r5d sql.NullFloat64) {
+	get := func(h string) sql.NullFloat64 {
+		v, ok := o.Returns[h]
+		return sql.NullFloat64{Float64: v, Valid: ok}
+	}
+	return get(storage.Horizon1h), get(storage.Horizon1d), get(storage.Horizon5d)
+}
+
+func setOutcomeReturns(o *storage.Outcome, r1h, r1d, r5d sql.NullFloat64) {
+	o.Returns = make(map[string]float64)
+	for h, v := range map[string]sql.NullFloat64{storage.Horizon1h: r1h, storage.Horizon1d: r1d, storage.Horizon5d: r5d} {
+		if v.Valid {
+			o.Returns[h] = v.Float64
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// EnqueueAlert implements storage.QueueStore
+func (s *Store) EnqueueAlert(ctx context.Context, a *storage.QueuedAlert) error {
+	var eventTime interface{}
+	if !a.EventTime.IsZero() {
+		eventTime = formatTime(a.EventTime)
+	}
+	queuedAt := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_queue (channel, target, chat_id, record_id, kind, symbol, signal, confidence, message, event_time, queued_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.Channel, a.Target, a.ChatID, a.RecordID, a.Kind, a.Symbol, a.Signal, a.Confidence, a.Message, eventTime, formatTime(queuedAt))
+	if err != nil {
+		return fmt.Errorf("failed to queue alert: %w", err)
+	}
+
+	if a.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to queue alert: %w", err)
+	}
+	a.QueuedAt = queuedAt
+	return nil
+}
+
+// ListQueuedAlerts implements storage.QueueStore
+func (s *Store) ListQueuedAlerts(ctx context.Context, channel string) ([]storage.QueuedAlert, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, channel, target, chat_id, record_id, kind, symbol, signal, confidence, message, event_time, queued_at
+		FROM alert_queue WHERE channel = ? ORDER BY queued_at, id`, channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert queue: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []storage.QueuedAlert
+	for rows.Next() {
+		var a storage.QueuedAlert
+		var eventTime sql.NullString
+		var queuedAt string
+		if err := rows.Scan(&a.ID, &a.Channel, &a.Target, &a.ChatID, &a.RecordID, &a.Kind, &a.Symbol, &a.Signal, &a.Confidence, &a.Message,
+			&eventTime, &queuedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan queued alert: %w", err)
+		}
+		if a.EventTime, err = parseTime(eventTime.String); err != nil {
+			return nil, fmt.Errorf("queued alert %d has invalid event time: %w", a.ID, err)
+		}
+		if a.QueuedAt, err = parseTime(queuedAt); err != nil {
+			return nil, fmt.Errorf("queued alert %d has invalid queue time: %w", a.ID, err)
+		}
+		alerts = append(alerts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query alert queue: %w", err)
+	}
+	return alerts, nil
+}
+
+// DeleteQueuedAlerts implements storage.QueueStore
+func (s *Store) DeleteQueuedAlerts(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM alert_queue WHERE id IN (`+placeholders+`)`, args...); err != nil {
+		return fmt.Errorf("failed to delete queued alerts: %w", err)
+	}
+	return nil
+}
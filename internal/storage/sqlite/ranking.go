@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// SaveRankings implements storage.RankingStore
+func (s *Store) SaveRankings(ctx context.Context, entries []storage.RankingEntry) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save rankings: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO ranking_history (signal_type, symbol, rank, score, confidence, ranked_at)
+		VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to save rankings: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		if _, err := stmt.ExecContext(ctx, e.SignalType, strings.ToUpper(e.Symbol), e.Rank, e.Score, e.Confidence,
+			formatTime(e.RankedAt)); err != nil {
+			return fmt.Errorf("failed to save ranking of %s: %w", e.Symbol, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save rankings: %w", err)
+	}
+	return nil
+}
+
+// LatestRankings implements storage.RankingStore
+func (s *Store) LatestRankings(ctx context.Context, signalType string) ([]storage.RankingEntry, error) {
+	return s.queryRankings(ctx, `
+		SELECT signal_type, symbol, rank, score, confidence, ranked_at FROM ranking_history
+		WHERE signal_type = ?1
+		  AND ranked_at = (SELECT MAX(ranked_at) FROM ranking_history WHERE signal_type = ?1)
+		ORDER BY rank`, signalType)
+}
+
+// SymbolRankings implements storage.RankingStore
+func (s *Store) SymbolRankings(ctx context.Context, signalType, symbol string, since time.Time) ([]storage.RankingEntry, error) {
+	return s.queryRankings(ctx, `
+		SELECT signal_type, symbol, rank, score, confidence, ranked_at FROM ranking_history
+		WHERE symbol = ? AND signal_type = ? AND ranked_at >= ?
+		ORDER BY ranked_at, id`, strings.ToUpper(symbol), signalType, formatTime(since))
+}
+
+// DeleteRankingsBefore implements storage.RankingStore
+func (s *Store) DeleteRankingsBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM ranking_history WHERE ranked_at < ?`, formatTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire rankings: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (s *Store) queryRankings(ctx context.Context, query string, args ...interface{}) ([]storage.RankingEntry, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ranking history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []storage.RankingEntry
+	for rows.Next() {
+		var e storage.RankingEntry
+		var rankedAt string
+		if err := rows.Scan(&e.SignalType, &e.Symbol, &e.Rank, &e.Score, &e.Confidence, &rankedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ranking: %w", err)
+		}
+		if e.RankedAt, err = parseTime(rankedAt); err != nil {
+			return nil, fmt.Errorf("ranking of %s has invalid time: %w", e.Symbol, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query ranking history: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/rules"
+)
+
+// LoadRules implements rules.Source
+func (s *Store) LoadRules(ctx context.Context) (*rules.RuleSet, error) {
+	set := &rules.RuleSet{Groups: make(map[string][]string)}
+
+	groupRows, err := s.db.QueryContext(ctx, `SELECT name, symbols FROM alert_rule_groups`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule groups: %w", err)
+	}
+	defer groupRows.Close()
+	for groupRows.Next() {
+		var name, symbols string
+		if err := groupRows.Scan(&name, &symbols); err != nil {
+			return nil, fmt.Errorf("failed to scan rule group: %w", err)
+		}
+		var members []string
+		if err := json.Unmarshal([]byte(symbols), &members); err != nil {
+			return nil, fmt.Errorf("rule group %q has invalid symbols: %w", name, err)
+		}
+		set.Groups[name] = members
+	}
+	if err := groupRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rule groups: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, rule_type, parameters, symbols, symbol_group, condition, message,
+		       trigger_mode, hysteresis, channels, cooldown_minutes, enabled
+		FROM alert_rules
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r rules.Rule
+		var params, symbols, channels string
+		var enabled bool
+		if err := rows.Scan(&r.ID, &r.Name, &r.Type, &params, &symbols, &r.Group, &r.Condition,
+			&r.Message, &r.Trigger, &r.Hysteresis, &channels, &r.CooldownMinutes, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan rule: %w", err)
+		}
+		if err := json.Unmarshal([]byte(params), &r.Params); err != nil {
+			return nil, fmt.Errorf("rule %q has invalid parameters: %w", r.ID, err)
+		}
+		if err := json.Unmarshal([]byte(symbols), &r.Symbols); err != nil {
+			return nil, fmt.Errorf("rule %q has invalid symbols: %w", r.ID, err)
+		}
+		if err := json.Unmarshal([]byte(channels), &r.Channels); err != nil {
+			return nil, fmt.Errorf("rule %q has invalid channels: %w", r.ID, err)
+		}
+		r.Enabled = &enabled
+		set.Rules = append(set.Rules, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rules: %w", err)
+	}
+
+	return set, nil
+}
+
+// SaveRule inserts or updates a rule
+func (s *Store) SaveRule(ctx context.Context, r rules.Rule) error {
+	params, err := marshalJSON(r.Params, "{}")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule parameters: %w", err)
+	}
+	symbols, err := marshalJSON(r.Symbols, "[]")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule symbols: %w", err)
+	}
+	channels, err := marshalJSON(r.Channels, "[]")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule channels: %w", err)
+	}
+	now := formatTime(time.Now())
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO alert_rules (id, name, rule_type, parameters, symbols, symbol_group, condition, message,
+		                         trigger_mode, hysteresis, channels, cooldown_minutes, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			rule_type = excluded.rule_type,
+			parameters = excluded.parameters,
+			symbols = excluded.symbols,
+			symbol_group = excluded.symbol_group,
+			condition = excluded.condition,
+			message = excluded.message,
+			trigger_mode = excluded.trigger_mode,
+			hysteresis = excluded.hysteresis,
+			channels = excluded.channels,
+			cooldown_minutes = excluded.cooldown_minutes,
+			enabled = excluded.enabled,
+			updated_at = excluded.updated_at`,
+		r.ID, r.Name, r.RuleType(), params, symbols, r.Group, r.Condition, r.Message,
+		r.TriggerMode(), r.Hysteresis, channels, r.CooldownMinutes, r.IsEnabled(), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to save rule %q: %w", r.ID, err)
+	}
+	return nil
+}
+
+// DeleteRule removes a rule
+func (s *Store) DeleteRule(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete rule %q: %w", id, err)
+	}
+	return nil
+}
+
+// SaveRuleGroup inserts or replaces a named symbol group
+func (s *Store) SaveRuleGroup(ctx context.Context, name string, symbols []string) error {
+	members, err := marshalJSON(symbols, "[]")
+	if err != nil {
+		return fmt.Errorf("failed to marshal group symbols: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO alert_rule_groups (name, symbols, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET symbols = excluded.symbols, updated_at = excluded.updated_at`,
+		name, members, formatTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to save rule group %q: %w", name, err)
+	}
+	return nil
+}
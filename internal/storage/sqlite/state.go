@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+// LoadCooldowns implements storage.CooldownStore
+func (s *Store) LoadCooldowns(ctx context.Context) ([]storage.Cooldown, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT cooldown_key, last_alert, signal, confidence, level FROM alert_cooldowns`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cooldowns: %w", err)
+	}
+	defer rows.Close()
+
+	var cooldowns []storage.Cooldown
+	for rows.Next() {
+		var c storage.Cooldown
+		var lastAlert string
+		if err := rows.Scan(&c.Key, &lastAlert, &c.Signal, &c.Confidence, &c.Level); err != nil {
+			return nil, fmt.Errorf("failed to scan cooldown: %w", err)
+		}
+		if c.LastAlert, err = parseTime(lastAlert); err != nil {
+			return nil, fmt.Errorf("cooldown %q has invalid time: %w", c.Key, err)
+		}
+		cooldowns = append(cooldowns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query cooldowns: %w", err)
+	}
+	return cooldowns, nil
+}
+
+// SaveCooldown implements storage.CooldownStore
+func (s *Store) SaveCooldown(ctx context.Context, c storage.Cooldown) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_cooldowns (cooldown_key, last_alert, signal, confidence, level) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (cooldown_key) DO UPDATE SET
+			last_alert = excluded.last_alert,
+			signal = excluded.signal,
+			confidence = excluded.confidence,
+			level = excluded.level`,
+		c.Key, formatTime(c.LastAlert), c.Signal, c.Confidence, c.Level)
+	if err != nil {
+		return fmt.Errorf("failed to save cooldown %q: %w", c.Key, err)
+	}
+	return nil
+}
+
+// DeleteCooldownsBefore implements storage.CooldownStore
+func (s *Store) DeleteCooldownsBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM alert_cooldowns WHERE last_alert < ?`, formatTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire cooldowns: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetMute implements storage.MuteStore
+func (s *Store) GetMute(ctx context.Context, symbol string, now time.Time) (*storage.Mute, error) {
+	var m storage.Mute
+	var until, createdAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT symbol, until, reason, created_at FROM symbol_mutes WHERE symbol = ? AND until > ?`,
+		strings.ToUpper(symbol), formatTime(now)).Scan(&m.Symbol, &until, &m.Reason, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mute for %s: %w", symbol, err)
+	}
+	if m.Until, err = parseTime(until); err != nil {
+		return nil, fmt.Errorf("mute for %s has invalid time: %w", symbol, err)
+	}
+	if m.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("mute for %s has invalid time: %w", symbol, err)
+	}
+	return &m, nil
+}
+
+// ListMutes implements storage.MuteStore
+func (s *Store) ListMutes(ctx context.Context, now time.Time) ([]storage.Mute, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT symbol, until, reason, created_at FROM symbol_mutes WHERE until > ? ORDER BY symbol`,
+		formatTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mutes: %w", err)
+	}
+	defer rows.Close()
+
+	var mutes []storage.Mute
+	for rows.Next() {
+		var m storage.Mute
+		var until, createdAt string
+		if err := rows.Scan(&m.Symbol, &until, &m.Reason, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan mute: %w", err)
+		}
+		if m.Until, err = parseTime(until); err != nil {
+			return nil, fmt.Errorf("mute for %s has invalid time: %w", m.Symbol, err)
+		}
+		if m.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("mute for %s has invalid time: %w", m.Symbol, err)
+		}
+		mutes = append(mutes, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query mutes: %w", err)
+	}
+	return mutes, nil
+}
+
+// SaveMute implements storage.MuteStore
+func (s *Store) SaveMute(ctx context.Context, m storage.Mute) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO symbol_mutes (symbol, until, reason, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (symbol) DO UPDATE SET until = excluded.until, reason = excluded.reason`,
+		strings.ToUpper(m.Symbol), formatTime(m.Until), m.Reason, formatTime(m.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save mute for %s: %w", m.Symbol, err)
+	}
+	return nil
+}
+
+// DeleteMute implements storage.MuteStore
+func (s *Store) DeleteMute(ctx context.Context, symbol string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM symbol_mutes WHERE symbol = ?`, strings.ToUpper(symbol)); err != nil {
+		return fmt.Errorf("failed to delete mute for %s: %w", symbol, err)
+	}
+	return nil
+}
+
+// ListSubscribers implements storage.SubscriberStore
+func (s *Store) ListSubscribers(ctx context.Context) ([]storage.Subscriber, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chat_id, name, enabled, quiet_hours, timezone, created_at FROM subscribers ORDER BY chat_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subscribers []storage.Subscriber
+	for rows.Next() {
+		var sub storage.Subscriber
+		var createdAt string
+		if err := rows.Scan(&sub.ChatID, &sub.Name, &sub.Enabled, &sub.QuietHours, &sub.Timezone, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		if sub.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("subscriber %d has invalid time: %w", sub.ChatID, err)
+		}
+		subscribers = append(subscribers, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query subscribers: %w", err)
+	}
+	return subscribers, nil
+}
+
+// SaveSubscriber implements storage.SubscriberStore
+func (s *Store) SaveSubscriber(ctx context.Context, sub storage.Subscriber) error {
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = time.Now()
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO subscribers (chat_id, name, enabled, quiet_hours, timezone, created_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (chat_id) DO UPDATE SET
+			name = excluded.name,
+			enabled = excluded.enabled,
+			quiet_hours = excluded.quiet_hours,
+			timezone = excluded.timezone`,
+		sub.ChatID, sub.Name, sub.Enabled, sub.QuietHours, sub.Timezone, formatTime(sub.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save subscriber %d: %w", sub.ChatID, err)
+	}
+	return nil
+}
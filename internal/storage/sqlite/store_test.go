@@ -0,0 +1,227 @@
+package sqlite
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/rules"
+	"github.com/trogers1052/alert-service/internal/storage"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	ctx := context.Background()
+	s, err := Open(ctx, filepath.Join(t.TempDir(), "alerts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return s
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	// A second run applies nothing and keeps the data
+	if err := s.SaveMute(ctx, storage.Mute{Symbol: "TSLA", Until: time.Now().Add(time.Hour), CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	if mutes, err := s.ListMutes(ctx, time.Now()); err != nil || len(mutes) != 1 {
+		t.Errorf("mutes after a second Migrate = %v, %v; want the one saved", mutes, err)
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var applied int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(files) {
+		t.Errorf("schema_migrations has %d versions, want %d", applied, len(files))
+	}
+}
+
+func TestRules(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+	disabled := false
+
+	saved := []rules.Rule{
+		{
+			ID:              "oversold",
+			Name:            "RSI oversold",
+			Group:           "tech",
+			Condition:       "rsi_14 < 30",
+			Message:         "Consider buying",
+			CooldownMinutes: 60,
+			Trigger:         rules.TriggerEdge,
+			Hysteresis:      5,
+			Channels:        []string{"telegram", "email"},
+		},
+		{
+			ID:      "breakout",
+			Type:    "PRICE_ABOVE",
+			Params:  map[string]float64{"price": 200},
+			Symbols: []string{"AAPL"},
+			Enabled: &disabled,
+		},
+	}
+	for _, r := range saved {
+		if err := s.SaveRule(ctx, r); err != nil {
+			t.Fatalf("SaveRule(%s): %v", r.ID, err)
+		}
+	}
+	if err := s.SaveRuleGroup(ctx, "tech", []string{"AAPL", "MSFT"}); err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := s.LoadRules(ctx)
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	if want := map[string][]string{"tech": {"AAPL", "MSFT"}}; !reflect.DeepEqual(set.Groups, want) {
+		t.Errorf("groups = %v, want %v", set.Groups, want)
+	}
+	if len(set.Rules) != 2 {
+		t.Fatalf("loaded %d rules, want 2", len(set.Rules))
+	}
+
+	// Rules load in ID order with defaults filled in
+	enabled := true
+	want := []rules.Rule{
+		{
+			ID:       "breakout",
+			Type:     "PRICE_ABOVE",
+			Params:   map[string]float64{"price": 200},
+			Symbols:  []string{"AAPL"},
+			Trigger:  rules.TriggerLevel,
+			Channels: []string{},
+			Enabled:  &disabled,
+		},
+		{
+			ID:              "oversold",
+			Name:            "RSI oversold",
+			Type:            rules.TypeExpression,
+			Params:          map[string]float64{},
+			Symbols:         []string{},
+			Group:           "tech",
+			Condition:       "rsi_14 < 30",
+			Message:         "Consider buying",
+			CooldownMinutes: 60,
+			Trigger:         rules.TriggerEdge,
+			Hysteresis:      5,
+			Channels:        []string{"telegram", "email"},
+			Enabled:         &enabled,
+		},
+	}
+	if !reflect.DeepEqual(set.Rules, want) {
+		t.Errorf("loaded rules:\n got %+v\nwant %+v", set.Rules, want)
+	}
+
+	// Saving an existing ID updates it; deleting removes it
+	updated := saved[0]
+	updated.Condition = "rsi_14 < 25"
+	if err := s.SaveRule(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteRule(ctx, "breakout"); err != nil {
+		t.Fatal(err)
+	}
+	set, err = s.LoadRules(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Rules) != 1 || set.Rules[0].Condition != "rsi_14 < 25" {
+		t.Errorf("rules after update and delete = %+v, want only oversold with the new condition", set.Rules)
+	}
+}
+
+func TestOutcomes(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+	at := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	pending := storage.Outcome{RecordID: 1, Symbol: "AAPL", Signal: "BUY", Confidence: 0.72,
+		Rules: []string{"rsi_oversold", "macd_cross"}, Alerted: true, EntryPrice: 187.5, AlertedAt: at,
+		Returns: map[string]float64{}}
+	complete := storage.Outcome{RecordID: 2, Symbol: "MSFT", Signal: "SELL", Confidence: 0.55, Rules: nil,
+		EntryPrice: 410, AlertedAt: at.Add(time.Hour),
+		Returns: map[string]float64{"1h": -0.01, "1d": 0.02, "5d": 0.035}}
+	old := storage.Outcome{RecordID: 3, Symbol: "TSLA", Signal: "BUY", EntryPrice: 250, AlertedAt: at.AddDate(0, 0, -60),
+		Returns: map[string]float64{}}
+	for _, o := range []storage.Outcome{pending, complete, old} {
+		if err := s.SaveOutcome(ctx, &o); err != nil {
+			t.Fatalf("SaveOutcome(%d): %v", o.RecordID, err)
+		}
+	}
+
+	// Saving again fills in returns and keeps the rest
+	pending.Returns = map[string]float64{"1h": 0.012}
+	pending.EntryPrice = 1
+	if err := s.SaveOutcome(ctx, &pending); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.ListOutcomes(ctx, at.AddDate(0, 0, -1), at.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("ListOutcomes: %v", err)
+	}
+	pending.EntryPrice = 187.5
+	complete.Rules = []string{}
+	if want := []storage.Outcome{pending, complete}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListOutcomes:\n got %+v\nwant %+v", got, want)
+	}
+
+	got, err = s.ListPendingOutcomes(ctx, at.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("ListPendingOutcomes: %v", err)
+	}
+	if len(got) != 1 || got[0].RecordID != 1 {
+		t.Errorf("pending outcomes = %+v, want only record 1", got)
+	}
+}
+
+func TestQueueChats(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	for _, alert := range []storage.QueuedAlert{
+		{Target: "telegram", ChatID: 7, Kind: storage.KindDecision, Symbol: "AAPL", Signal: "BUY", Confidence: 0.8, Message: "AAPL"},
+		{Target: "email", Kind: storage.KindDecision, Symbol: "AAPL", Signal: "BUY", Confidence: 0.8, Message: "AAPL"},
+		{Channel: "email", RecordID: 3, Kind: storage.KindDecision, Symbol: "MSFT", Signal: "SELL", Message: "MSFT"},
+	} {
+		if err := s.EnqueueAlert(ctx, &alert); err != nil {
+			t.Fatalf("EnqueueAlert: %v", err)
+		}
+	}
+
+	quiet, err := s.ListQueuedAlerts(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quiet) != 2 || quiet[0].Target != "telegram" || quiet[0].ChatID != 7 || quiet[1].Target != "email" {
+		t.Fatalf("quiet hours queue = %+v, want telegram chat 7 then email", quiet)
+	}
+
+	if err := s.DeleteQueuedAlerts(ctx, []int64{quiet[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+	quiet, _ = s.ListQueuedAlerts(ctx, "")
+	email, _ := s.ListQueuedAlerts(ctx, "email")
+	if len(quiet) != 1 || quiet[0].Target != "email" || len(email) != 1 || email[0].RecordID != 3 {
+		t.Errorf("after deleting chat 7's alert: quiet %+v, email %+v", quiet, email)
+	}
+}
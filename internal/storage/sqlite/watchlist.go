@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/watchlist"
+)
+
+// ListWatchlists implements storage.WatchlistStore
+func (s *Store) ListWatchlists(ctx context.Context) ([]watchlist.Watchlist, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, symbols, active, updated_at FROM watchlists ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	var lists []watchlist.Watchlist
+	for rows.Next() {
+		var w watchlist.Watchlist
+		var symbols, updatedAt string
+		if err := rows.Scan(&w.Name, &symbols, &w.Active, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist: %w", err)
+		}
+		if err := json.Unmarshal([]byte(symbols), &w.Symbols); err != nil {
+			return nil, fmt.Errorf("watchlist %q has invalid symbols: %w", w.Name, err)
+		}
+		if w.UpdatedAt, err = parseTime(updatedAt); err != nil {
+			return nil, fmt.Errorf("watchlist %q has invalid time: %w", w.Name, err)
+		}
+		lists = append(lists, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query watchlists: %w", err)
+	}
+	return lists, nil
+}
+
+// SaveWatchlist implements storage.WatchlistStore
+func (s *Store) SaveWatchlist(ctx context.Context, w watchlist.Watchlist) error {
+	w = watchlist.Normalize(w)
+	symbols, err := marshalJSON(w.Symbols, "[]")
+	if err != nil {
+		return fmt.Errorf("failed to marshal watchlist symbols: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO watchlists (name, symbols, active, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			symbols = excluded.symbols,
+			active = excluded.active,
+			updated_at = excluded.updated_at`,
+		w.Name, symbols, w.Active, formatTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to save watchlist %q: %w", w.Name, err)
+	}
+	return nil
+}
+
+// DeleteWatchlist implements storage.WatchlistStore
+func (s *Store) DeleteWatchlist(ctx context.Context, name string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM watchlists WHERE name = ?`, watchlist.NormalizeName(name)); err != nil {
+		return fmt.Errorf("failed to delete watchlist %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/trogers1052/alert-service/internal/rules"
+)
+
+// Store is the persistence interface implemented by every storage backend
+type Store interface {
+	RuleStore
+	HistoryStore
+	CooldownStore
+	MuteStore
+	SubscriberStore
+	QueueStore
+	OutcomeStore
+	RankingStore
+	WatchlistStore
+
+	// Migrate applies pending schema migrations
+	Migrate(ctx context.Context) error
+	Close() error
+}
+
+// RuleStore provides the custom alert rules and symbol groups. Rules are
+// written to the database directly; the backends' SaveRule, DeleteRule and
+// SaveRuleGroup exist for seeding and tests.
+type RuleStore interface {
+	rules.Source
+}
+
+// RuleChangeNotifier is implemented by backends that can push rule changes
+// instead of relying on polling alone
+type RuleChangeNotifier interface {
+	ListenRuleChanges(ctx context.Context) (<-chan struct{}, error)
+}
+
+// Cooldown is the last alert sent for a cooldown key
+type Cooldown struct {
+	Key        string
+	LastAlert  time.Time
+	Signal     string
+	Confidence float64
+	Level      int // escalation level of an adaptive cooldown; 0 is the base duration
+}
+
+// CooldownStore persists cooldown timestamps so they survive restarts
+type CooldownStore interface {
+	LoadCooldowns(ctx context.Context) ([]Cooldown, error)
+	SaveCooldown(ctx context.Context, cooldown Cooldown) error
+	DeleteCooldownsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Mute silences alerts for a symbol until a point in time
+type Mute struct {
+	Symbol    string
+	Until     time.Time
+	Reason    string
+	CreatedAt time.Time
+}
+
+// MuteStore persists symbol mutes
+type MuteStore interface {
+	// GetMute returns the symbol's mute if it is still active
+	GetMute(ctx context.Context, symbol string, now time.Time) (*Mute, error)
+	ListMutes(ctx context.Context, now time.Time) ([]Mute, error)
+	SaveMute(ctx context.Context, mute Mute) error
+	DeleteMute(ctx context.Context, symbol string) error
+}
+
+// Subscriber is a Telegram chat that receives alerts
+type Subscriber struct {
+	ChatID     int64
+	Name       string
+	Enabled    bool
+	QuietHours string // per-subscriber quiet hours; empty uses the global setting
+	Timezone   string // IANA zone for QuietHours
+	CreatedAt  time.Time
+}
+
+// SubscriberStore persists alert subscribers
+type SubscriberStore interface {
+	ListSubscribers(ctx context.Context) ([]Subscriber, error)
+	SaveSubscriber(ctx context.Context, subscriber Subscriber) error
+}
+
+// EnsureSubscriber registers chatID as an enabled subscriber if there are
+// no subscribers yet, so a fresh store delivers to the configured chat
+func EnsureSubscriber(ctx context.Context, s SubscriberStore, chatID int64) error {
+	subscribers, err := s.ListSubscribers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(subscribers) > 0 {
+		return nil
+	}
+	return s.SaveSubscriber(ctx, Subscriber{ChatID: chatID, Name: "default", Enabled: true})
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/trogers1052/alert-service/internal/watchlist"
+)
+
+// WatchlistStore persists named watchlists
+type WatchlistStore interface {
+	ListWatchlists(ctx context.Context) ([]watchlist.Watchlist, error)
+	SaveWatchlist(ctx context.Context, w watchlist.Watchlist) error
+	DeleteWatchlist(ctx context.Context, name string) error
+}
+
+// EnsureWatchlists stores the given watchlists if the store has none yet,
+// so configured watchlists seed a fresh store, and returns the stored ones
+func EnsureWatchlists(ctx context.Context, s WatchlistStore, seed []watchlist.Watchlist) ([]watchlist.Watchlist, error) {
+	lists, err := s.ListWatchlists(ctx)
+	if err != nil || len(lists) > 0 {
+		return lists, err
+	}
+	for _, w := range seed {
+		if err := s.SaveWatchlist(ctx, w); err != nil {
+			return nil, err
+		}
+	}
+	return s.ListWatchlists(ctx)
+}
@@ -0,0 +1,186 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const telegramUpdatesURL = "https://api.telegram.org/bot%s/getUpdates"
+
+// pollTimeout is the long-poll timeout passed to getUpdates
+const pollTimeout = 30 * time.Second
+
+// CommandFunc handles a bot command and returns the HTML reply
+type CommandFunc func(ctx context.Context, chatID int64, args []string) (string, error)
+
+// AuthorizeFunc reports whether a chat may use bot commands
+type AuthorizeFunc func(ctx context.Context, chatID int64) bool
+
+// Bot answers commands sent to the bot, e.g. /detail 42, by long polling
+// getUpdates. It cannot run while the bot has a webhook configured.
+type Bot struct {
+	client    *Client
+	commands  map[string]CommandFunc
+	help      map[string]string
+	authorize AuthorizeFunc
+	offset    int64
+}
+
+// NewBot creates a command bot that replies through client. Only the
+// client's configured chat may use commands until SetAuthorizer is called.
+func NewBot(client *Client) *Bot {
+	b := &Bot{
+		client:   client,
+		commands: make(map[string]CommandFunc),
+		help:     make(map[string]string),
+	}
+	b.authorize = func(ctx context.Context, chatID int64) bool {
+		return chatID == client.chatID
+	}
+	b.Handle("help", "list commands", b.helpCommand)
+	return b
+}
+
+// Handle registers a command without its leading slash
+func (b *Bot) Handle(command, help string, fn CommandFunc) {
+	command = strings.ToLower(command)
+	b.commands[command] = fn
+	b.help[command] = help
+}
+
+// SetAuthorizer replaces the check for which chats may use commands
+func (b *Bot) SetAuthorizer(fn AuthorizeFunc) {
+	b.authorize = fn
+}
+
+type update struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+type updatesResponse struct {
+	OK          bool     `json:"ok"`
+	Description string   `json:"description,omitempty"`
+	Result      []update `json:"result"`
+}
+
+// Run polls for commands until the context is cancelled
+func (b *Bot) Run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		updates, err := b.poll(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to poll Telegram commands: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = time.Second
+
+		for _, u := range updates {
+			b.offset = u.UpdateID + 1
+			if u.Message != nil {
+				b.dispatch(ctx, u.Message.Chat.ID, u.Message.Text)
+			}
+		}
+	}
+}
+
+func (b *Bot) poll(ctx context.Context) ([]update, error) {
+	params := url.Values{}
+	params.Set("timeout", strconv.Itoa(int(pollTimeout.Seconds())))
+	params.Set("offset", strconv.FormatInt(b.offset, 10))
+	params.Set("allowed_updates", `["message"]`)
+	endpoint := fmt.Sprintf(telegramUpdatesURL, b.client.botToken) + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// The long poll outlasts the client's default timeout
+	httpClient := &http.Client{Timeout: pollTimeout + 10*time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response updatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if !response.OK {
+		return nil, fmt.Errorf("telegram API error: %s", response.Description)
+	}
+	return response.Result, nil
+}
+
+// dispatch runs a command and replies to the chat it came from
+func (b *Bot) dispatch(ctx context.Context, chatID int64, text string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return
+	}
+
+	// Commands may be addressed as /detail@my_bot in group chats
+	command, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	fn, ok := b.commands[strings.ToLower(command)]
+	if !ok {
+		return
+	}
+	if !b.authorize(ctx, chatID) {
+		log.Printf("Ignoring /%s from unauthorized chat %d", command, chatID)
+		return
+	}
+
+	reply, err := fn(ctx, chatID, fields[1:])
+	if err != nil {
+		log.Printf("Command /%s from chat %d failed: %v", command, chatID, err)
+		reply = "⚠️ " + html.EscapeString(err.Error())
+	}
+	if reply == "" {
+		return
+	}
+	if err := b.client.SendMessageTo(ctx, chatID, reply, "HTML"); err != nil {
+		log.Printf("Failed to reply to /%s in chat %d: %v", command, chatID, err)
+	}
+}
+
+func (b *Bot) helpCommand(ctx context.Context, chatID int64, args []string) (string, error) {
+	names := make([]string, 0, len(b.commands))
+	for name := range b.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("<b>Commands</b>\n")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("/%s - %s\n", name, html.EscapeString(b.help[name])))
+	}
+	return sb.String(), nil
+}
@@ -4,18 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"time"
 )
 
 const telegramAPIURL = "https://api.telegram.org/bot%s/sendMessage"
 
+// RecipientsFunc returns the chat IDs a message should be delivered to
+type RecipientsFunc func(ctx context.Context) ([]int64, error)
+
 // Client handles Telegram Bot API interactions
 type Client struct {
 	botToken   string
 	chatID     int64
+	recipients RecipientsFunc
 	httpClient *http.Client
 }
 
@@ -30,6 +36,18 @@ func NewClient(botToken string, chatID int64) *Client {
 	}
 }
 
+// Name identifies the client as a notification channel
+func (c *Client) Name() string {
+	return "telegram"
+}
+
+// SetRecipients delivers messages to the chats returned by fn instead of
+// the configured chat. The configured chat is still used when fn fails;
+// when fn returns no chats the message is not delivered.
+func (c *Client) SetRecipients(fn RecipientsFunc) {
+	c.recipients = fn
+}
+
 // SendMessageRequest represents a Telegram sendMessage request
 type SendMessageRequest struct {
 	ChatID    int64  `json:"chat_id"`
@@ -48,12 +66,37 @@ func (c *Client) SendMessage(ctx context.Context, message string) error {
 	return c.SendMessageWithParseMode(ctx, message, "HTML")
 }
 
-// SendMessageWithParseMode sends a message with a specific parse mode
+// SendMessageWithParseMode sends a message with a specific parse mode to
+// every recipient
 func (c *Client) SendMessageWithParseMode(ctx context.Context, message, parseMode string) error {
+	var errs []error
+	for _, chatID := range c.chatIDs(ctx) {
+		if err := c.SendMessageTo(ctx, chatID, message, parseMode); err != nil {
+			errs = append(errs, fmt.Errorf("chat %d: %w", chatID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// chatIDs resolves the chats to deliver to
+func (c *Client) chatIDs(ctx context.Context) []int64 {
+	if c.recipients == nil {
+		return []int64{c.chatID}
+	}
+	ids, err := c.recipients(ctx)
+	if err != nil {
+		log.Printf("Failed to resolve Telegram recipients, using default chat: %v", err)
+		return []int64{c.chatID}
+	}
+	return ids
+}
+
+// SendMessageTo sends a message to a specific chat
+func (c *Client) SendMessageTo(ctx context.Context, chatID int64, message, parseMode string) error {
 	url := fmt.Sprintf(telegramAPIURL, c.botToken)
 
 	reqBody := SendMessageRequest{
-		ChatID:    c.chatID,
+		ChatID:    chatID,
 		Text:      message,
 		ParseMode: parseMode,
 	}
@@ -0,0 +1,128 @@
+package trend
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sparkBlocks are the sparkline levels, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Point is one confidence reading of a symbol and signal
+type Point struct {
+	Confidence float64
+	At         time.Time
+}
+
+// Tracker keeps the recent confidence readings of each symbol and signal.
+// It is safe for concurrent use.
+type Tracker struct {
+	mu     sync.Mutex
+	size   int           // readings kept per symbol and signal
+	maxAge time.Duration // older readings are dropped; zero keeps them
+	series map[string][]Point
+}
+
+// NewTracker creates a tracker keeping up to size readings no older than maxAge
+func NewTracker(size int, maxAge time.Duration) *Tracker {
+	return &Tracker{size: size, maxAge: maxAge, series: make(map[string][]Point)}
+}
+
+func key(symbol, signal string) string {
+	return strings.ToUpper(symbol) + ":" + strings.ToUpper(signal)
+}
+
+// Add records a reading and returns the symbol and signal's readings,
+// oldest first. Readings older than the latest one are placed in order.
+func (t *Tracker) Add(symbol, signal string, p Point) []Point {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	k := key(symbol, signal)
+	points := append(t.series[k], p)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].At.Before(points[j].At) })
+	points = t.trim(points, time.Now())
+	t.series[k] = points
+	return append([]Point(nil), points...)
+}
+
+// Get returns the symbol and signal's readings, oldest first
+func (t *Tracker) Get(symbol, signal string) []Point {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	k := key(symbol, signal)
+	points := t.trim(t.series[k], time.Now())
+	if len(points) == 0 {
+		delete(t.series, k)
+		return nil
+	}
+	t.series[k] = points
+	return append([]Point(nil), points...)
+}
+
+// trim drops readings beyond the size limit or older than maxAge
+func (t *Tracker) trim(points []Point, now time.Time) []Point {
+	if t.maxAge > 0 {
+		cutoff := now.Add(-t.maxAge)
+		first := 0
+		for first < len(points) && points[first].At.Before(cutoff) {
+			first++
+		}
+		points = points[first:]
+	}
+	if len(points) > t.size {
+		points = points[len(points)-t.size:]
+	}
+	return points
+}
+
+// Streak returns how many consecutive increases end at the latest reading
+func Streak(points []Point) int {
+	n := 0
+	for i := len(points) - 1; i > 0; i-- {
+		if points[i].Confidence <= points[i-1].Confidence {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// Crossed reports whether the latest reading reached threshold while the
+// one before it was below
+func Crossed(points []Point, threshold float64) bool {
+	if len(points) < 2 {
+		return false
+	}
+	return points[len(points)-1].Confidence >= threshold && points[len(points)-2].Confidence < threshold
+}
+
+// Sparkline renders readings as block characters scaled between their
+// lowest and highest value, e.g. "▁▃▅█"
+func Sparkline(points []Point) string {
+	if len(points) == 0 {
+		return ""
+	}
+	low, high := points[0].Confidence, points[0].Confidence
+	for _, p := range points {
+		if p.Confidence < low {
+			low = p.Confidence
+		}
+		if p.Confidence > high {
+			high = p.Confidence
+		}
+	}
+
+	var sb strings.Builder
+	for _, p := range points {
+		level := len(sparkBlocks) / 2
+		if high > low {
+			level = int((p.Confidence - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
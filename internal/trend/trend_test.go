@@ -0,0 +1,85 @@
+package trend
+
+import (
+	"testing"
+	"time"
+)
+
+func series(confidences ...float64) []Point {
+	start := time.Now().Add(-time.Hour)
+	points := make([]Point, len(confidences))
+	for i, c := range confidences {
+		points[i] = Point{Confidence: c, At: start.Add(time.Duration(i) * time.Minute)}
+	}
+	return points
+}
+
+func TestStreakAndCrossed(t *testing.T) {
+	tests := []struct {
+		name    string
+		points  []Point
+		streak  int
+		crossed bool
+	}{
+		{"empty", nil, 0, false},
+		{"single", series(0.7), 0, false},
+		{"rising above", series(0.5, 0.6, 0.7, 0.8), 3, false},
+		{"crossing", series(0.5, 0.6, 0.75), 2, true},
+		{"streak ends at the latest", series(0.9, 0.5, 0.6, 0.7), 2, true},
+		{"flat breaks the streak", series(0.5, 0.6, 0.6), 0, false},
+		{"falling", series(0.8, 0.7), 0, false},
+		{"already above", series(0.75, 0.8), 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Streak(tt.points); got != tt.streak {
+				t.Errorf("Streak() = %d, want %d", got, tt.streak)
+			}
+			if got := Crossed(tt.points, 0.7); got != tt.crossed {
+				t.Errorf("Crossed(0.7) = %v, want %v", got, tt.crossed)
+			}
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		points []Point
+		want   string
+	}{
+		{nil, ""},
+		{series(0.5, 0.5), "▅▅"},
+		{series(0.2, 0.9), "▁█"},
+		{series(0.5, 0.6, 0.7, 0.8, 0.9), "▁▂▄▆█"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.points); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.points, got, tt.want)
+		}
+	}
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Now()
+	tracker := NewTracker(3, time.Hour)
+
+	tracker.Add("aapl", "buy", Point{Confidence: 0.1, At: now.Add(-2 * time.Hour)}) // too old
+	tracker.Add("AAPL", "BUY", Point{Confidence: 0.6, At: now.Add(-10 * time.Minute)})
+	tracker.Add("AAPL", "BUY", Point{Confidence: 0.5, At: now.Add(-20 * time.Minute)}) // out of order
+	tracker.Add("AAPL", "BUY", Point{Confidence: 0.7, At: now.Add(-5 * time.Minute)})
+	got := tracker.Add("AAPL", "BUY", Point{Confidence: 0.8, At: now})
+
+	want := []float64{0.6, 0.7, 0.8}
+	if len(got) != len(want) {
+		t.Fatalf("Add() kept %d readings, want %d", len(got), len(want))
+	}
+	for i, p := range got {
+		if p.Confidence != want[i] {
+			t.Errorf("reading %d = %.1f, want %.1f", i, p.Confidence, want[i])
+		}
+	}
+	if other := tracker.Get("AAPL", "SELL"); other != nil {
+		t.Errorf("Get() for another signal = %v, want nil", other)
+	}
+}
@@ -0,0 +1,167 @@
+package watchlist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watchlist is a named group of symbols, e.g. "metals": SLV, GLD. Only
+// active watchlists take part in filtering, routing and cooldowns.
+type Watchlist struct {
+	Name      string    `json:"name"`
+	Symbols   []string  `json:"symbols"`
+	Active    bool      `json:"active"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Has reports whether the watchlist contains a symbol
+func (w Watchlist) Has(symbol string) bool {
+	symbol = NormalizeSymbol(symbol)
+	for _, s := range w.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeName lower-cases a watchlist name
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NormalizeSymbol upper-cases a symbol
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// Normalize cleans a watchlist's name and symbols, dropping duplicates and
+// sorting the symbols
+func Normalize(w Watchlist) Watchlist {
+	w.Name = NormalizeName(w.Name)
+	seen := make(map[string]bool, len(w.Symbols))
+	symbols := make([]string, 0, len(w.Symbols))
+	for _, s := range w.Symbols {
+		if s = NormalizeSymbol(s); s != "" && !seen[s] {
+			seen[s] = true
+			symbols = append(symbols, s)
+		}
+	}
+	sort.Strings(symbols)
+	w.Symbols = symbols
+	return w
+}
+
+// Parse reads watchlists in the form "metals=SLV,GLD; core-tech=AAPL,MSFT".
+// Parsed watchlists are active.
+func Parse(spec string) ([]Watchlist, error) {
+	var lists []Watchlist
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, symbols, ok := strings.Cut(entry, "=")
+		w := Normalize(Watchlist{Name: name, Symbols: strings.Split(symbols, ","), Active: true})
+		if !ok || w.Name == "" || len(w.Symbols) == 0 {
+			return nil, fmt.Errorf("invalid watchlist %q, want name=SYMBOL,SYMBOL", strings.TrimSpace(entry))
+		}
+		if seen[w.Name] {
+			return nil, fmt.Errorf("watchlist %q is defined twice", w.Name)
+		}
+		seen[w.Name] = true
+		lists = append(lists, w)
+	}
+	return lists, nil
+}
+
+// Set holds the current watchlists. It is safe for concurrent use.
+type Set struct {
+	mu    sync.RWMutex
+	lists map[string]Watchlist
+}
+
+// NewSet creates a set holding the given watchlists
+func NewSet(lists []Watchlist) *Set {
+	s := &Set{}
+	s.Replace(lists)
+	return s
+}
+
+// Replace swaps in a full set of watchlists
+func (s *Set) Replace(lists []Watchlist) {
+	next := make(map[string]Watchlist, len(lists))
+	for _, w := range lists {
+		w = Normalize(w)
+		next[w.Name] = w
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists = next
+}
+
+// Put adds or replaces a watchlist
+func (s *Set) Put(w Watchlist) {
+	w = Normalize(w)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[w.Name] = w
+}
+
+// Delete removes a watchlist
+func (s *Set) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lists, NormalizeName(name))
+}
+
+// Get returns a watchlist by name
+func (s *Set) Get(name string) (Watchlist, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	w, ok := s.lists[NormalizeName(name)]
+	return w, ok
+}
+
+// All returns every watchlist sorted by name
+func (s *Set) All() []Watchlist {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lists := make([]Watchlist, 0, len(s.lists))
+	for _, w := range s.lists {
+		lists = append(lists, w)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Name < lists[j].Name })
+	return lists
+}
+
+// HasActive reports whether any watchlist is active
+func (s *Set) HasActive() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.lists {
+		if w.Active {
+			return true
+		}
+	}
+	return false
+}
+
+// ListsOf returns the names of the active watchlists containing a symbol,
+// sorted
+func (s *Set) ListsOf(symbol string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var names []string
+	for _, w := range s.lists {
+		if w.Active && w.Has(symbol) {
+			names = append(names, w.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,74 @@
+package watchlist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	lists, err := Parse(" Metals = slv, GLD ,slv; core-tech=AAPL,MSFT;")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Watchlist{
+		{Name: "metals", Symbols: []string{"GLD", "SLV"}, Active: true},
+		{Name: "core-tech", Symbols: []string{"AAPL", "MSFT"}, Active: true},
+	}
+	if !reflect.DeepEqual(lists, want) {
+		t.Errorf("Parse() = %+v, want %+v", lists, want)
+	}
+
+	for spec, wantErr := range map[string]string{
+		"metals":                "want name=SYMBOL,SYMBOL",
+		"=SLV":                  "want name=SYMBOL,SYMBOL",
+		"metals= , ":            "want name=SYMBOL,SYMBOL",
+		"metals=SLV;METALS=GLD": `watchlist "metals" is defined twice`,
+	} {
+		if _, err := Parse(spec); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Parse(%q) error = %v, want %q", spec, err, wantErr)
+		}
+	}
+	if lists, err := Parse(""); err != nil || len(lists) != 0 {
+		t.Errorf("Parse(\"\") = %v, %v; want no watchlists", lists, err)
+	}
+}
+
+func TestSet(t *testing.T) {
+	set := NewSet([]Watchlist{
+		{Name: "Metals", Symbols: []string{"slv", "GLD"}, Active: true},
+		{Name: "tech", Symbols: []string{"AAPL", "GLD"}, Active: true},
+		{Name: "old", Symbols: []string{"AAPL"}},
+	})
+
+	if got := set.ListsOf(" gld "); !reflect.DeepEqual(got, []string{"metals", "tech"}) {
+		t.Errorf("ListsOf(gld) = %v, want [metals tech]", got)
+	}
+	if got := set.ListsOf("AAPL"); !reflect.DeepEqual(got, []string{"tech"}) {
+		t.Errorf("ListsOf(AAPL) = %v, want only the active tech list", got)
+	}
+	if got := set.ListsOf("TSLA"); len(got) != 0 {
+		t.Errorf("ListsOf(TSLA) = %v, want none", got)
+	}
+	if w, ok := set.Get(" METALS "); !ok || !w.Has("slv") {
+		t.Errorf("Get(METALS) = %+v, %v; want metals with SLV", w, ok)
+	}
+
+	set.Put(Watchlist{Name: "TECH", Symbols: []string{"msft"}, Active: false})
+	set.Delete("metals")
+	if set.HasActive() {
+		t.Error("HasActive() = true with every list inactive")
+	}
+	var names []string
+	for _, w := range set.All() {
+		names = append(names, w.Name)
+	}
+	if strings.Join(names, ",") != "old,tech" {
+		t.Errorf("All() = %v, want old and tech sorted", names)
+	}
+
+	set.Replace(nil)
+	if set.HasActive() || len(set.All()) != 0 {
+		t.Error("Replace(nil) kept watchlists")
+	}
+}